@@ -3,6 +3,7 @@ package cling
 import (
 	"io"
 	"os"
+	"slices"
 )
 
 type CLI struct {
@@ -11,10 +12,25 @@ type CLI struct {
 	longDescription string
 	version         string
 	commands        []*Command
+	root            *Command
+	defaultCommand  string
+	helpTopics      []*helpTopic
+	helpTemplate    string
+	usageTemplate   string
+	helpOrder       HelpOrder
+	commandDepth    int
+	treeCommand     bool
 
-	preRun  CommandHook
-	postRun CommandHook
+	preRun      CommandHook
+	postRun     CommandHook
+	middlewares []Middleware
 
+	interactive  bool
+	nameFromArgs bool
+	multiCall    bool
+	lookupEnv    func(key string) (string, bool)
+
+	stdin  io.Reader
 	stdout io.Writer
 	stderr io.Writer
 }
@@ -25,6 +41,71 @@ func (cli *CLI) WithCommand(command *Command) *CLI {
 	return cli
 }
 
+// WithAction sets the action that runs when no command is given
+func (cli *CLI) WithAction(action CommandHandler) *CLI {
+	cli.root.action = action
+	return cli
+}
+
+// WithFlag adds a flag to the action of the CLI
+func (cli *CLI) WithFlag(flag CmdFlag) *CLI {
+	cli.root.WithFlag(flag)
+	return cli
+}
+
+// WithArgument adds an argument to the action of the CLI
+func (cli *CLI) WithArgument(arg CmdArg) *CLI {
+	cli.root.WithArgument(arg)
+	return cli
+}
+
+// WithDefaultCommand sets the top level command that runs when no command is given
+func (cli *CLI) WithDefaultCommand(name string) *CLI {
+	cli.defaultCommand = name
+	return cli
+}
+
+// WithHelpTopic adds a help page that is not tied to a command, shown by 'help <name>'
+func (cli *CLI) WithHelpTopic(name string, body string) *CLI {
+	cli.helpTopics = append(cli.helpTopics, &helpTopic{name: name, body: body})
+	return cli
+}
+
+// WithHelpTemplate sets the text/template used to render the help of commands.
+// The template is executed with HelpData. See DefaultHelpTemplate for the default.
+func (cli *CLI) WithHelpTemplate(text string) *CLI {
+	cli.helpTemplate = text
+	return cli
+}
+
+// WithUsageTemplate sets the text/template used to render the usage of the CLI.
+// The template is executed with UsageData. See DefaultUsageTemplate for the default.
+func (cli *CLI) WithUsageTemplate(text string) *CLI {
+	cli.usageTemplate = text
+	return cli
+}
+
+// WithHelpOrder sets the order commands, flags and their groups are listed in the help.
+// Defaults to HelpOrderDeclaration.
+func (cli *CLI) WithHelpOrder(order HelpOrder) *CLI {
+	cli.helpOrder = order
+	return cli
+}
+
+// WithCommandDepth sets how many levels of commands are listed in the usage and the help of commands.
+// A depth of 0 lists the full command tree, which is the default.
+func (cli *CLI) WithCommandDepth(depth int) *CLI {
+	cli.commandDepth = depth
+	return cli
+}
+
+// WithTreeCommand adds a built-in 'tree' command that prints the command hierarchy with descriptions.
+// 'tree <command>' prints the hierarchy below the given command.
+func (cli *CLI) WithTreeCommand() *CLI {
+	cli.treeCommand = true
+	return cli
+}
+
 // WithPreRun sets the pre-run hook for the CLI
 func (cli *CLI) WithPreRun(hook CommandHook) *CLI {
 	cli.preRun = hook
@@ -37,6 +118,124 @@ func (cli *CLI) WithPostRun(hook CommandHook) *CLI {
 	return cli
 }
 
+// Use adds middlewares that wrap the action of every command.
+// They run outside the middlewares of the commands, in the order they were added.
+func (cli *CLI) Use(middlewares ...Middleware) *CLI {
+	cli.middlewares = append(cli.middlewares, middlewares...)
+	return cli
+}
+
+// WithNameFromArgs makes the CLI take its name from the first command line argument
+// instead of the name it was created with.
+func (cli *CLI) WithNameFromArgs() *CLI {
+	cli.nameFromArgs = true
+	return cli
+}
+
+// WithMultiCall enables busybox style invocation: when the CLI is invoked through a name
+// (usually a symlink) that matches a top level command, that command is run.
+func (cli *CLI) WithMultiCall() *CLI {
+	cli.multiCall = true
+	return cli
+}
+
+// WithInteractive enables prompting for missing required flags and arguments
+// when stdin is an interactive terminal.
+// Prompting can be disabled for a single run with the --no-input flag.
+func (cli *CLI) WithInteractive() *CLI {
+	cli.interactive = true
+	return cli
+}
+
+// WithIO sets the streams the CLI reads from and writes to.
+// Handlers can access them with Stdin, Stdout and Stderr.
+// Prompts read their answers from the reader. To be treated as an interactive terminal,
+// the reader must be a terminal or implement the Terminal interface.
+func (cli *CLI) WithIO(in io.Reader, out io.Writer, err io.Writer) *CLI {
+	cli.stdin = in
+	cli.stdout = out
+	cli.stderr = err
+	return cli
+}
+
+// ExitWithMessage behaves like the package level ExitWithMessage,
+// but prints the message to the stderr of the CLI.
+func (cli *CLI) ExitWithMessage(err error) {
+	exitWithError(cli.stderr, err, true)
+}
+
+// WithEnvLookup sets the function used to read environment variables for flags with env sources.
+// Defaults to os.LookupEnv.
+func (cli *CLI) WithEnvLookup(lookup func(key string) (string, bool)) *CLI {
+	cli.lookupEnv = lookup
+	return cli
+}
+
+// Name returns the name of the CLI
+func (cli *CLI) Name() string {
+	return cli.name
+}
+
+// Version returns the version of the CLI
+func (cli *CLI) Version() string {
+	return cli.version
+}
+
+// Description returns the description of the CLI
+func (cli *CLI) Description() string {
+	return cli.description
+}
+
+// LongDescription returns the long description of the CLI
+func (cli *CLI) LongDescription() string {
+	return cli.longDescription
+}
+
+// Commands returns the top level commands of the CLI
+func (cli *CLI) Commands() []*Command {
+	return slices.Clone(cli.commands)
+}
+
+// Flags returns the flags of the action of the CLI
+func (cli *CLI) Flags() []CmdFlag {
+	return cli.root.Flags()
+}
+
+// Arguments returns the arguments of the action of the CLI
+func (cli *CLI) Arguments() []CmdArg {
+	return cli.root.Arguments()
+}
+
+// IO returns the streams the CLI reads from and writes to
+func (cli *CLI) IO() (in io.Reader, out io.Writer, err io.Writer) {
+	return cli.stdin, cli.stdout, cli.stderr
+}
+
+// EnvLookup returns the function used to read environment variables
+func (cli *CLI) EnvLookup() func(key string) (string, bool) {
+	return cli.lookupEnv
+}
+
+// Walk calls fn for every command of the CLI, including hidden ones, depth first in declaration order.
+// The path lists the commands from the top level command down to cmd, including it.
+// Walking stops at the first error fn returns, which is returned by Walk.
+func (cli *CLI) Walk(fn func(path []*Command, cmd *Command) error) error {
+	return walkCommands(nil, cli.commands, fn)
+}
+
+func walkCommands(parents []*Command, commands []*Command, fn func(path []*Command, cmd *Command) error) error {
+	for _, cmd := range commands {
+		path := append(slices.Clone(parents), cmd)
+		if err := fn(path, cmd); err != nil {
+			return err
+		}
+		if err := walkCommands(path, cmd.children, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // WithDescription sets the description of the CLI
 func (cli *CLI) WithDescription(description string) *CLI {
 	cli.description = description
@@ -52,12 +251,17 @@ func (cli *CLI) WithLongDescription(longDescription string) *CLI {
 // NewCLI creates a new CLI
 func NewCLI(name string, version string) *CLI {
 	cli := &CLI{
-		name:    name,
-		version: version,
-		stdout:  os.Stdout,
-		stderr:  os.Stderr,
-		preRun:  NoOpHook,
-		postRun: NoOpHook,
+		name:          name,
+		version:       version,
+		root:          NewCommand("", nil),
+		helpTemplate:  DefaultHelpTemplate,
+		usageTemplate: DefaultUsageTemplate,
+		stdin:         os.Stdin,
+		stdout:        os.Stdout,
+		stderr:        os.Stderr,
+		lookupEnv:     os.LookupEnv,
+		preRun:        NoOpHook,
+		postRun:       NoOpHook,
 	}
 	return cli
 }
@@ -4,65 +4,102 @@ import (
 	"context"
 	stdErrs "errors"
 	"fmt"
-	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/pkg/errors"
 )
 
 var ErrInvalidCLIngConfig = errors.New("invalid CLIng configuration")
+var ErrUnknownCommand = errors.New("unknown command")
 
 // Run executes the CLI with the given command line arguments.
-func (c *CLI) Run(ctx context.Context, args []string) error {
-	// get the executable name
-	exec, err := os.Executable()
-	if err != nil {
-		return errors.Wrap(ErrInvalidCLIngConfig, "could not resolve executable name")
-	}
-	c.name = filepath.Base(exec)
-
+func (c *CLI) Run(ctx context.Context, args []string) (runErr error) {
 	// validate the CLI
 	if err := c.validate(); err != nil {
 		return err
 	}
 
 	// parse the arguments
-	flags, positionals := parseArguments(args)
-	if len(positionals) < 2 {
-		c.printUsage()
-		return errors.New("missing command")
+	tokens := tokenizeArguments(args)
+	if idx := slices.IndexFunc(tokens, func(t argToken) bool { return t.flag == nil }); idx >= 0 {
+		invokedAs := filepath.Base(tokens[idx].positional)
+
+		switch {
+		case c.multiCall && c.findCommand([]string{invokedAs}) != nil:
+			// invoked through a link named after a command
+			tokens[idx] = argToken{positional: invokedAs, raw: []string{invokedAs}}
+		default:
+			if c.nameFromArgs {
+				c.name = invokedAs
+			}
+			// remove the executable name
+			tokens = slices.Delete(tokens, idx, idx+1)
+		}
 	}
 
-	// remove the executable name
-	positionals = positionals[1:]
+	expandShortFlags(tokens)
+
+	// --no-input disables all prompting for this run
+	// --no-color disables styling of help for this run
+	// they are handled here and left out of the invocation, but stay in the arguments the command is given
+	orderedFlags := tokenFlags(tokens)
+	noInput := slices.ContainsFunc(orderedFlags, func(f FlagValue) bool { return f.Name == "no-input" })
+	noColor := slices.ContainsFunc(orderedFlags, func(f FlagValue) bool { return f.Name == noColorFlag })
+	orderedFlags = slices.DeleteFunc(orderedFlags, func(f FlagValue) bool {
+		return f.Name == "no-input" || f.Name == noColorFlag
+	})
+	flags := flagsByName(orderedFlags)
+	positionals := tokenPositionals(tokens)
+	format := c.helpFormat(noColor)
 
 	// do we have a --version in the flags
 	if _, ok := flags["version"]; ok {
-		fmt.Printf("%s v%s\n", c.name, c.version)
+		fmt.Fprintf(c.stdout, "%s v%s\n", c.name, c.version)
 		return nil
 	}
 
-	command := c.findCommand(positionals)
-	if command == nil {
-		c.printUsage()
-		return nil
+	if _, ok := flags[schemaFlag]; ok {
+		return c.printSchema()
+	}
+
+	_, help := flags["help"]
+	if help && len(positionals) == 0 {
+		return c.printUsage(format)
 	}
 
-	pathToRoot := command.pathToRoot()
-	positionals = positionals[len(pathToRoot):]
+	if len(positionals) > 0 && positionals[0] == helpCommand && c.findCommand(positionals[:1]) == nil {
+		return c.runHelp(positionals[1:], format)
+	}
 
-	if _, ok := flags["help"]; ok {
-		return command.printHelp(c)
+	if c.treeCommand && len(positionals) > 0 && positionals[0] == treeCommand && c.findCommand(positionals[:1]) == nil {
+		return c.runTree(positionals[1:], format)
+	}
+
+	command, rest, err := c.resolveCommand(positionals)
+	if err != nil {
+		return err
+	}
+	if command == nil {
+		if err := c.printUsage(format); err != nil {
+			return err
+		}
+		return errors.New("missing command")
+	}
+
+	if help {
+		return command.printHelp(c, format)
 	}
 
 	// verify that there are no required arguments after an optional one
 	for idx, arg := range command.arguments {
-		isThisRequired := arg.isRequired()
+		isThisRequired := arg.IsRequired()
 		isThereAnymore := idx < (len(command.arguments) - 1)
 		if !isThereAnymore {
 			break
 		}
-		isNextRequired := command.arguments[idx+1].isRequired()
+		isNextRequired := command.arguments[idx+1].IsRequired()
 
 		if !isThisRequired && isThereAnymore && isNextRequired {
 			return fmt.Errorf("required argument %s after optional argument %s", arg.Name(), command.arguments[idx+1].Name())
@@ -71,49 +108,129 @@ func (c *CLI) Run(ctx context.Context, args []string) error {
 
 	// verify that all flags are either required or have a default value
 	for _, flag := range command.flags {
-		if !flag.isRequired() && !flag.hasDefault() {
+		if !flag.IsRequired() && !flag.HasDefault() {
 			return fmt.Errorf("flag %s has no default value but is not required", flag.Name())
 		}
 	}
 
-	newArgs := append(positionals, reconstructCmdLineFromFlags(flags)...)
+	// prompts share a single reader so that buffered input is not lost between them
+	prompter := newPrompter(c.stdin, c.stderr)
+	if err := c.confirm(command, flags, prompter, noInput); err != nil {
+		return err
+	}
+
+	// the command gets the arguments after the command path exactly as they were given
+	newArgs := tokenArgs(withoutCommandPath(tokens, len(positionals)-len(rest)))
+
+	invocation := &Invocation{
+		Command:     command,
+		Path:        command.commandPath(),
+		Args:        slices.Clone(args),
+		Flags:       orderedFlags,
+		Positionals: slices.Clone(rest),
+	}
+	hookContext := &HookContext{Command: command, Invocation: invocation}
 
 	ctx = contextWithCommand(ctx, command)
-	if c.preRun != nil {
-		if err := c.preRun(ctx, newArgs); err != nil {
-			return err
+	ctx = contextWithInvocation(ctx, invocation)
+	ctx = contextWithHookContext(ctx, hookContext)
+	ctx = contextWithIO(ctx, c.stdin, c.stdout, c.stderr)
+	ctx = contextWithEnvLookup(ctx, c.lookupEnv)
+	if c.interactive && !noInput && isTerminal(c.stdin) {
+		ctx = contextWithPrompter(ctx, prompter)
+	}
+	// the post-run hook runs even if the run failed or panicked, so that it can clean up
+	defer func() {
+		r := recover()
+		if r != nil {
+			hookContext.Err = errors.Wrapf(ErrPanic, "%v", r)
 		}
+		if c.postRun != nil {
+			if err := c.postRun(ctx, newArgs); err != nil {
+				// if post run throws an error - join with the error of the run
+				runErr = stdErrs.Join(runErr, err)
+			}
+		}
+		if r != nil {
+			panic(r)
+		}
+	}()
+
+	var execErr error
+	if c.preRun != nil {
+		execErr = c.preRun(ctx, newArgs)
+		hookContext.Err = execErr
 	}
 
-	execErr := command.execute(ctx, newArgs)
-	if execErr != nil {
+	if execErr == nil {
+		execErr = command.execute(ctx, newArgs, c.middlewares)
 		if errors.Is(execErr, ErrInvalidCommand) {
-			c.printUsage()
+			// the usage is best effort - the error of the command is what matters
+			_ = c.printUsage(format)
 		}
+		hookContext.Err = execErr
 	}
+	return execErr
+}
 
-	if c.postRun != nil {
-		if err := c.postRun(ctx, newArgs); err != nil {
-			// if post run throws an error - join with the execErr
-			execErr = stdErrs.Join(execErr, err)
+// withoutCommandPath removes the first n positionals, which name the command to run, from the tokens.
+// Flags before and between them are kept in order.
+func withoutCommandPath(tokens []argToken, n int) []argToken {
+	return slices.DeleteFunc(slices.Clone(tokens), func(t argToken) bool {
+		if t.flag != nil || n == 0 {
+			return false
 		}
-	}
-	return execErr
+		n--
+		return true
+	})
 }
 
-func reconstructCmdLineFromFlags(f map[string][]string) []string {
-	flags := []string{}
-	for k, v := range f {
-		for _, val := range v {
-			flags = append(flags, fmt.Sprintf("--%s=%s", k, val))
+// resolveCommand finds the command to run for the given positionals
+// and returns it along with the positionals left for its arguments.
+// When no command matches, the default command or the action of the CLI is used.
+//
+// A command with children only receives positionals that do not name one of its children
+// if it has an action and declares arguments - otherwise they are reported as an unknown command.
+func (c *CLI) resolveCommand(positionals []string) (*Command, []string, error) {
+	if len(positionals) > 0 {
+		if command := c.findCommand(positionals); command != nil {
+			rest := positionals[len(command.pathToRoot()):]
+			if len(rest) > 0 && len(command.children) > 0 && !command.acceptsArguments() {
+				return nil, rest, unknownCommandError(rest[0], c.name, command.commandPath(), command.children)
+			}
+			return command, rest, nil
 		}
 	}
-	return flags
+
+	var fallback *Command
+	if c.defaultCommand != "" {
+		fallback = c.findCommand([]string{c.defaultCommand})
+	} else if c.root.action != nil {
+		fallback = c.root
+	}
+	if len(positionals) == 0 || (fallback != nil && fallback.acceptsArguments()) {
+		return fallback, positionals, nil
+	}
+	return nil, positionals, unknownCommandError(positionals[0], c.name, nil, c.commands)
+}
+
+func unknownCommandError(name string, cliName string, path []string, valid []*Command) error {
+	names := make([]string, 0, len(valid))
+	for _, cmd := range visibleCommands(valid) {
+		names = append(names, cmd.name)
+	}
+	return errors.Wrapf(
+		ErrUnknownCommand,
+		"'%s' is not a command of '%s' (valid commands: %s)",
+		name,
+		strings.Join(append([]string{cliName}, path...), " "),
+		strings.Join(names, ", "),
+	)
 }
 
 func (c *CLI) findCommand(names []string) *Command {
 	for _, cmd := range c.commands {
-		if cmd.name == names[0] {
+		if cmd.hasName(names[0]) {
 			return c.findCmd(cmd, names, 1)
 		}
 	}
@@ -125,7 +242,7 @@ func (c *CLI) findCmd(currentCMD *Command, names []string, index int) *Command {
 		return currentCMD
 	}
 	for _, child := range currentCMD.children {
-		if child.name == names[index] {
+		if child.hasName(names[index]) {
 			return c.findCmd(child, names, index+1)
 		}
 	}
@@ -141,6 +258,34 @@ func (c *CLI) validate() error {
 		return errors.Wrap(ErrInvalidCLIngConfig, "stderr channel not set")
 	}
 
+	if c.stdin == nil {
+		return errors.Wrap(ErrInvalidCLIngConfig, "stdin channel not set")
+	}
+
+	if c.lookupEnv == nil {
+		return errors.Wrap(ErrInvalidCLIngConfig, "environment lookup not set")
+	}
+
+	if _, err := parseTemplate("help", c.helpTemplate, helpFormat{}); err != nil {
+		return errors.Wrapf(ErrInvalidCLIngConfig, "invalid help template: %v", err)
+	}
+
+	if _, err := parseTemplate("usage", c.usageTemplate, helpFormat{}); err != nil {
+		return errors.Wrapf(ErrInvalidCLIngConfig, "invalid usage template: %v", err)
+	}
+
+	if err := c.root.validateFlagsAndArgs(); err != nil {
+		return err
+	}
+
+	if c.defaultCommand != "" && c.findCommand([]string{c.defaultCommand}) == nil {
+		return errors.Wrapf(ErrInvalidCLIngConfig, "default command '%s' not found", c.defaultCommand)
+	}
+
+	if err := validateCommandNames(c.commands); err != nil {
+		return err
+	}
+
 	for _, cmd := range c.commands {
 		if err := cmd.validate(); err != nil {
 			return err
@@ -1,8 +1,13 @@
 package cling
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -38,7 +43,7 @@ func action(ctx context.Context, args []string) error {
 	if err := Hydrate(ctx, args, cfg); err != nil {
 		return err
 	}
-	fmt.Println(cfg)
+	fmt.Fprintln(Stdout(ctx), cfg)
 	return nil
 }
 
@@ -167,3 +172,873 @@ func TestHelp(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestIO(t *testing.T) {
+	stdout := bytes.NewBuffer(nil)
+	cli := NewCLI("test", "0.0.1").
+		WithIO(strings.NewReader("input"), stdout, io.Discard).
+		WithCommand(NewCommand("echo", func(ctx context.Context, args []string) error {
+			_, err := io.Copy(Stdout(ctx), Stdin(ctx))
+			return err
+		}))
+
+	ctx := context.Background()
+	if err := cli.Run(ctx, []string{"test", "echo"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stdout.String() != "input" {
+		t.Fatalf("expected stdin to be copied to stdout, got: %q", stdout.String())
+	}
+
+	stdout.Reset()
+	if err := cli.Run(ctx, []string{"test", "echo", "--version"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "v0.0.1") {
+		t.Fatalf("expected version on stdout, got: %q", stdout.String())
+	}
+}
+
+func TestProgramName(t *testing.T) {
+	newCLI := func(stdout io.Writer) *CLI {
+		return NewCLI("tool", "0.0.1").
+			WithIO(strings.NewReader(""), stdout, io.Discard).
+			WithCommand(NewCommand("ls", func(ctx context.Context, args []string) error {
+				_, err := fmt.Fprintf(Stdout(ctx), "ls %v", args)
+				return err
+			}))
+	}
+
+	tests := []struct {
+		name   string
+		setup  func(cli *CLI) *CLI
+		args   []string
+		stdout string
+	}{
+		{"configured name", func(cli *CLI) *CLI { return cli }, []string{"/usr/bin/other", "ls", "--version"}, "tool v0.0.1\n"},
+		{"name from args", (*CLI).WithNameFromArgs, []string{"/usr/bin/other", "ls", "--version"}, "other v0.0.1\n"},
+		{"multi call", (*CLI).WithMultiCall, []string{"/usr/bin/ls", "dir"}, "ls [dir]"},
+		{"multi call through main name", (*CLI).WithMultiCall, []string{"/usr/bin/tool", "ls", "dir"}, "ls [dir]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout := bytes.NewBuffer(nil)
+			if err := tt.setup(newCLI(stdout)).Run(context.Background(), tt.args); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if stdout.String() != tt.stdout {
+				t.Fatalf("expected %q, got %q", tt.stdout, stdout.String())
+			}
+		})
+	}
+}
+
+func TestRootAction(t *testing.T) {
+	type rootConfig struct {
+		Input string `cling-name:"input"`
+		File  string `cling-name:"file"`
+	}
+
+	stdout := bytes.NewBuffer(nil)
+	cli := NewCLI("mytool", "0.0.1").
+		WithIO(strings.NewReader(""), stdout, io.Discard).
+		WithAction(func(ctx context.Context, args []string) error {
+			cfg := &rootConfig{}
+			if err := Hydrate(ctx, args, cfg); err != nil {
+				return err
+			}
+			_, err := fmt.Fprintf(Stdout(ctx), "%s %s", cfg.Input, cfg.File)
+			return err
+		}).
+		WithFlag(NewStringCmdInput("input").Required().AsFlag()).
+		WithArgument(NewStringCmdInput("file").Required().AsArgument()).
+		WithCommand(NewCommand("sub", func(ctx context.Context, args []string) error {
+			_, err := fmt.Fprint(Stdout(ctx), "sub")
+			return err
+		}))
+
+	tests := []struct {
+		args   []string
+		stdout string
+	}{
+		{[]string{"mytool", "--input", "x", "file.txt"}, "x file.txt"},
+		{[]string{"mytool", "sub"}, "sub"},
+		{[]string{"mytool", "--version"}, "mytool v0.0.1\n"},
+	}
+	for _, tt := range tests {
+		stdout.Reset()
+		if err := cli.Run(context.Background(), tt.args); err != nil {
+			t.Fatalf("unexpected error for %v: %v", tt.args, err)
+		}
+		if stdout.String() != tt.stdout {
+			t.Fatalf("expected %q for %v, got %q", tt.stdout, tt.args, stdout.String())
+		}
+	}
+
+	stdout.Reset()
+	if err := cli.Run(context.Background(), []string{"mytool", "--help"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "mytool <file> [flags]") || !strings.Contains(stdout.String(), "--input") {
+		t.Fatalf("expected root usage in help, got %q", stdout.String())
+	}
+
+	stdout.Reset()
+	cli = NewCLI("mytool", "0.0.1").
+		WithIO(strings.NewReader(""), stdout, io.Discard).
+		WithCommand(NewCommand("sub", func(ctx context.Context, args []string) error {
+			_, err := fmt.Fprintf(Stdout(ctx), "sub %v", args)
+			return err
+		}).WithArgument(NewStringCmdInput("file").Required().AsArgument())).
+		WithDefaultCommand("sub")
+	if err := cli.Run(context.Background(), []string{"mytool", "file.txt"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stdout.String() != "sub [file.txt]" {
+		t.Fatalf("expected default command to run, got %q", stdout.String())
+	}
+}
+
+func TestParentWithAction(t *testing.T) {
+	newCLI := func(parent *Command) *CLI {
+		return NewCLI("test", "0.0.1").
+			WithIO(strings.NewReader(""), io.Discard, io.Discard).
+			WithCommand(parent.WithChildCommand(NewCommand("child", NoOpHook)))
+	}
+
+	tests := []struct {
+		name    string
+		parent  *Command
+		args    []string
+		wantErr error
+	}{
+		{"child", NewCommand("parent", NoOpHook), []string{"test", "parent", "child"}, nil},
+		{"parent", NewCommand("parent", NoOpHook), []string{"test", "parent"}, nil},
+		{"unknown child", NewCommand("parent", NoOpHook), []string{"test", "parent", "chlid"}, ErrUnknownCommand},
+		{"unknown child without action", NewCommand("parent", nil), []string{"test", "parent", "chlid"}, ErrUnknownCommand},
+		{
+			"argument",
+			NewCommand("parent", NoOpHook).WithArgument(NewStringCmdInput("arg").AsArgument()),
+			[]string{"test", "parent", "value"},
+			nil,
+		},
+		{"unknown top level", NewCommand("parent", NoOpHook), []string{"test", "prent"}, ErrUnknownCommand},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newCLI(tt.parent).Run(context.Background(), tt.args)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	err := newCLI(NewCommand("parent", NoOpHook)).Run(context.Background(), []string{"test", "parent", "chlid"})
+	if !strings.Contains(err.Error(), "'chlid' is not a command of 'test parent' (valid commands: child)") {
+		t.Fatalf("unexpected error message: %v", err)
+	}
+}
+
+func TestHelpCommand(t *testing.T) {
+	stdout := bytes.NewBuffer(nil)
+	cli := NewCLI("test", "0.0.1").
+		WithIO(strings.NewReader(""), stdout, io.Discard).
+		WithHelpTopic("environment", "Environment variables\n\nTEST_HOME sets the home directory.").
+		WithCommand(
+			NewCommand("parent", nil).
+				WithChildCommand(NewCommand("child", NoOpHook).WithLongDescription("Child long description")),
+		)
+
+	tests := []struct {
+		args     []string
+		contains string
+	}{
+		{[]string{"test", "help"}, "Help Topics:\n  environment  Environment variables"},
+		{[]string{"test", "help"}, "help     Help about any command or topic"},
+		{[]string{"test", "help", "environment"}, "TEST_HOME sets the home directory."},
+		{[]string{"test", "help", "parent", "child"}, "Child long description"},
+		{[]string{"test", "parent", "child", "-h"}, "Child long description"},
+		{[]string{"test", "-h"}, "Available Commands:"},
+	}
+	for _, tt := range tests {
+		stdout.Reset()
+		if err := cli.Run(context.Background(), tt.args); err != nil {
+			t.Fatalf("unexpected error for %v: %v", tt.args, err)
+		}
+		if !strings.Contains(stdout.String(), tt.contains) {
+			t.Fatalf("expected %q in output for %v, got %q", tt.contains, tt.args, stdout.String())
+		}
+	}
+
+	if err := cli.Run(context.Background(), []string{"test", "help", "parent", "nope"}); !errors.Is(err, ErrUnknownCommand) {
+		t.Fatalf("expected ErrUnknownCommand, got %v", err)
+	}
+}
+
+func TestHelpTemplate(t *testing.T) {
+	stdout := bytes.NewBuffer(nil)
+	cli := NewCLI("test", "0.0.1").
+		WithIO(strings.NewReader(""), stdout, io.Discard).
+		WithHelpTemplate(`{{.CommandPath}} ({{join .Aliases ","}}){{range .FlagGroups}}{{range .Flags}} --{{.Name}}={{.Default}}{{end}}{{end}}{{range .EnvVars}} ${{.Name}}{{end}}`).
+		WithUsageTemplate(`{{.Name}}:{{range .Commands}} {{.Path}}{{end}}`).
+		WithCommand(
+			NewCommand("remove", NoOpHook).
+				WithAliases("rm", "del").
+				WithFlag(NewIntCmdInput("depth").WithDefault(3).AsFlag().FromEnv([]string{"DEPTH"})),
+		)
+
+	if err := cli.Run(context.Background(), []string{"test", "rm", "--help"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stdout.String() != "test remove (rm,del) --depth=3 $DEPTH" {
+		t.Fatalf("unexpected help output: %q", stdout.String())
+	}
+
+	stdout.Reset()
+	if err := cli.Run(context.Background(), []string{"test", "--help"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stdout.String() != "test: remove help" {
+		t.Fatalf("unexpected usage output: %q", stdout.String())
+	}
+
+	if err := cli.WithHelpTemplate("{{.Broken").Run(context.Background(), []string{"test", "rm"}); !errors.Is(err, ErrInvalidCLIngConfig) {
+		t.Fatalf("expected ErrInvalidCLIngConfig, got: %v", err)
+	}
+}
+
+func TestAliasClashes(t *testing.T) {
+	tests := []struct {
+		name     string
+		commands []*Command
+		wantErr  bool
+	}{
+		{"distinct", []*Command{NewCommand("remove", NoOpHook).WithAliases("rm"), NewCommand("list", NoOpHook).WithAliases("ls")}, false},
+		{"repeated alias", []*Command{NewCommand("remove", NoOpHook).WithAliases("r"), NewCommand("restart", NoOpHook).WithAliases("r")}, true},
+		{"alias of a sibling name", []*Command{NewCommand("remove", NoOpHook).WithAliases("list"), NewCommand("list", NoOpHook)}, true},
+		{"alias of own name", []*Command{NewCommand("remove", NoOpHook).WithAliases("remove")}, true},
+		{"repeated name", []*Command{NewCommand("remove", NoOpHook), NewCommand("remove", NoOpHook)}, true},
+		{
+			"children",
+			[]*Command{NewCommand("db", nil).
+				WithChildCommand(NewCommand("migrate", NoOpHook).WithAliases("m")).
+				WithChildCommand(NewCommand("merge", NoOpHook).WithAliases("m"))},
+			true,
+		},
+		{
+			"same alias under different parents",
+			[]*Command{
+				NewCommand("db", nil).WithChildCommand(NewCommand("migrate", NoOpHook).WithAliases("m")),
+				NewCommand("queue", nil).WithChildCommand(NewCommand("move", NoOpHook).WithAliases("m")),
+			},
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := NewCLI("test", "0.0.1").WithIO(strings.NewReader(""), io.Discard, io.Discard)
+			for _, cmd := range tt.commands {
+				cli.WithCommand(cmd)
+			}
+			err := cli.Run(context.Background(), []string{"test", "--help"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error: %v, got: %v", tt.wantErr, err)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidCLIngConfig) {
+				t.Fatalf("expected ErrInvalidCLIngConfig, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestExamples(t *testing.T) {
+	newCLI := func(deploy *Command) *CLI {
+		return NewCLI("test", "0.0.1").
+			WithCommand(
+				deploy.
+					WithArgument(NewStringCmdInput("service").WithValidator(NewEnumValidator("api", "web")).Required().AsArgument()).
+					WithFlag(NewStringCmdInput("env").Required().AsFlag()).
+					WithFlag(NewIntCmdInput("port").WithValidator(NewIntRangeValidator(1, 65535)).WithDefault(8080).AsFlag()).
+					WithFlag(NewBoolCmdInput("dry-run").WithDefault(false).AsFlag()),
+			).
+			WithCommand(NewCommand("status", NoOpHook))
+	}
+
+	stdout := bytes.NewBuffer(nil)
+	cli := newCLI(
+		NewCommand("deploy", NoOpHook).
+			WithExample("Deploy the API to production", "test deploy api --env prod").
+			WithExample("", "test deploy web --env staging --dry-run").
+			WithExample("", "test deploy web --env=staging --port 9090").
+			WithSeeAlso("status"),
+	).WithIO(strings.NewReader(""), stdout, io.Discard)
+
+	if err := cli.ValidateExamples(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := cli.Run(context.Background(), []string{"test", "deploy", "--help"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "Examples:\n  # Deploy the API to production\n  test deploy api --env prod\n\n  test deploy web --env staging --dry-run\n\n  test deploy web --env=staging --port 9090\n\nSee Also:\n  test status\n"
+	if !strings.Contains(stdout.String(), want) {
+		t.Fatalf("expected examples in help, got %q", stdout.String())
+	}
+
+	invalid := []string{
+		"deploy api --env prod",
+		"test status --env prod",
+		"test deploy api --env prod --force",
+		"test deploy api",
+		"test deploy --env prod",
+		"test deploy api web --env prod",
+		"test deploy 'api --env prod",
+		"test deploy api --env prod --port abc",
+		"test deploy api --env prod --port 0",
+		"test deploy api --env prod --dry-run=maybe",
+		"test deploy db --env prod",
+		"test deploy api --env",
+	}
+	for _, commandLine := range invalid {
+		cli := newCLI(NewCommand("deploy", NoOpHook).WithExample("", commandLine))
+		if err := cli.ValidateExamples(); err == nil {
+			t.Fatalf("expected example %q to be invalid", commandLine)
+		}
+	}
+	for _, commandLine := range []string{"test deploy api --env prod --no-color", "test deploy api --env prod --no-input", "test deploy api --env prod -h"} {
+		cli := newCLI(NewCommand("deploy", NoOpHook).WithExample("", commandLine))
+		if err := cli.ValidateExamples(); err != nil {
+			t.Fatalf("expected example %q with a built-in flag to be valid, got: %v", commandLine, err)
+		}
+	}
+	if err := newCLI(NewCommand("deploy", NoOpHook).WithExample("", "test deploy api --env prod --yes")).ValidateExamples(); err == nil {
+		t.Fatal("expected --yes to be invalid for a command without confirmation")
+	}
+	for _, commandLine := range invalid[7:] {
+		cli := newCLI(NewCommand("deploy", NoOpHook).WithExample("", commandLine))
+		if err := cli.ValidateExamples(); !errors.Is(err, ErrInvalidExample) {
+			t.Fatalf("expected example %q to be invalid with ErrInvalidExample, got: %v", commandLine, err)
+		}
+	}
+
+	if err := newCLI(NewCommand("deploy", NoOpHook).WithSeeAlso("nope")).ValidateExamples(); !errors.Is(err, ErrInvalidExample) {
+		t.Fatalf("expected ErrInvalidExample, got: %v", err)
+	}
+}
+
+func TestHelpGroups(t *testing.T) {
+	newCLI := func(stdout io.Writer) *CLI {
+		return NewCLI("test", "0.0.1").
+			WithIO(strings.NewReader(""), stdout, io.Discard).
+			WithCommand(NewCommand("version", NoOpHook).WithDescription("Show the version")).
+			WithCommand(NewCommand("scale", NoOpHook).WithDescription("Scale a cluster").WithGroup("Cluster Management")).
+			WithCommand(NewCommand("create", NoOpHook).WithDescription("Create a cluster").WithGroup("Cluster Management")).
+			WithCommand(NewCommand("apply", NoOpHook).WithDescription("Apply a manifest").WithGroup("Deployment")).
+			WithCommand(
+				NewCommand("expose", NoOpHook).
+					WithFlag(NewIntCmdInput("port").WithDefault(80).AsFlag().WithGroup("Networking")).
+					WithFlag(NewStringCmdInput("name").WithDefault("web").AsFlag()).
+					WithFlag(NewStringCmdInput("host").WithDefault("localhost").AsFlag().WithGroup("Networking")),
+			)
+	}
+
+	stdout := bytes.NewBuffer(nil)
+	if err := newCLI(stdout).Run(context.Background(), []string{"test", "--help"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "Available Commands:\n  version  Show the version\n  expose\n  help     Help about any command or topic\n\n" +
+		"Cluster Management Commands:\n  scale   Scale a cluster\n  create  Create a cluster\n\n" +
+		"Deployment Commands:\n  apply  Apply a manifest\n"
+	if !strings.Contains(trimTrailingSpaces(stdout.String()), want) {
+		t.Fatalf("expected grouped commands, got %q", stdout.String())
+	}
+
+	stdout.Reset()
+	if err := newCLI(stdout).WithHelpOrder(HelpOrderAlphabetical).Run(context.Background(), []string{"test", "--help"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want = "Available Commands:\n  expose\n  version  Show the version\n  help     Help about any command or topic\n\n" +
+		"Cluster Management Commands:\n  create  Create a cluster\n  scale   Scale a cluster\n"
+	if !strings.Contains(trimTrailingSpaces(stdout.String()), want) {
+		t.Fatalf("expected sorted commands, got %q", stdout.String())
+	}
+
+	stdout.Reset()
+	if err := newCLI(stdout).WithHelpOrder(HelpOrderAlphabetical).Run(context.Background(), []string{"test", "expose", "--help"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := stdout.String()
+	flags := strings.Index(got, "\nFlags:")
+	networking := strings.Index(got, "\nNetworking Flags:")
+	if flags < 0 || networking < flags {
+		t.Fatalf("expected ungrouped flags before the networking group, got %q", got)
+	}
+	if host, port := strings.Index(got, "--host"), strings.Index(got, "--port"); host < networking || port < host {
+		t.Fatalf("expected sorted networking flags, got %q", got)
+	}
+	if name := strings.Index(got, "--name"); name < flags || name > networking {
+		t.Fatalf("expected --name in the ungrouped flags, got %q", got)
+	}
+}
+
+func TestCommandTree(t *testing.T) {
+	stdout := bytes.NewBuffer(nil)
+	cli := NewCLI("test", "0.0.1").
+		WithIO(strings.NewReader(""), stdout, io.Discard).
+		WithTreeCommand().
+		WithCommand(
+			NewCommand("cluster", nil).
+				WithDescription("Manage clusters").
+				WithChildCommand(
+					NewCommand("node", nil).
+						WithDescription("Manage nodes").
+						WithChildCommand(NewCommand("drain", NoOpHook).WithDescription("Drain a node")),
+				).
+				WithChildCommand(NewCommand("create", NoOpHook).WithDescription("Create a cluster")),
+		).
+		WithCommand(NewCommand("status", NoOpHook).WithDescription("Show the status"))
+
+	if err := cli.Run(context.Background(), []string{"test", "--help"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "Available Commands:\n" +
+		"  cluster    Manage clusters\n" +
+		"    node     Manage nodes\n" +
+		"      drain  Drain a node\n" +
+		"    create   Create a cluster\n" +
+		"  status     Show the status\n" +
+		"  help       Help about any command or topic\n" +
+		"  tree       Show the command tree\n"
+	if !strings.Contains(trimTrailingSpaces(stdout.String()), want) {
+		t.Fatalf("expected the full command tree, got %q", stdout.String())
+	}
+
+	stdout.Reset()
+	if err := cli.WithCommandDepth(1).Run(context.Background(), []string{"test", "cluster", "--help"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want = "Available Commands:\n  node    Manage nodes\n  create  Create a cluster\n\n"
+	if !strings.Contains(trimTrailingSpaces(stdout.String()), want) {
+		t.Fatalf("expected one level of commands, got %q", stdout.String())
+	}
+
+	stdout.Reset()
+	if err := cli.Run(context.Background(), []string{"test", "tree"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want = "test\n" +
+		"  ├── cluster        Manage clusters\n" +
+		"  │   ├── node       Manage nodes\n" +
+		"  │   │   └── drain  Drain a node\n" +
+		"  │   └── create     Create a cluster\n" +
+		"  └── status         Show the status\n"
+	if got := trimTrailingSpaces(stdout.String()); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	if err := cli.Run(context.Background(), []string{"test", "tree", "nope"}); !errors.Is(err, ErrUnknownCommand) {
+		t.Fatalf("expected ErrUnknownCommand, got %v", err)
+	}
+}
+
+// trimTrailingSpaces removes the padding tables add at the end of lines.
+func trimTrailingSpaces(s string) string {
+	lines := strings.Split(s, "\n")
+	for idx, line := range lines {
+		lines[idx] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+type terminalWriter struct {
+	*bytes.Buffer
+}
+
+func (t *terminalWriter) IsTerminal() bool {
+	return true
+}
+
+func TestHelpFormat(t *testing.T) {
+	run := func(stdout io.Writer, env map[string]string, args ...string) {
+		cli := NewCLI("test", "0.0.1").
+			WithIO(strings.NewReader(""), stdout, io.Discard).
+			WithEnvLookup(func(key string) (string, bool) {
+				value, ok := env[key]
+				return value, ok
+			}).
+			WithCommand(
+				NewCommand("deploy", NoOpHook).
+					WithLongDescription("Deploy builds the service and rolls it out to every region one after the other").
+					WithFlag(NewStringCmdInput("env").Required().WithDescription("The environment to deploy the service to").AsFlag()),
+			)
+		if err := cli.Run(context.Background(), append([]string{"test", "deploy", "--help"}, args...)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	stdout := bytes.NewBuffer(nil)
+	run(stdout, map[string]string{"COLUMNS": "40"})
+	want := "Deploy builds the service and rolls it\nout to every region one after the other\nUsage:\n"
+	if !strings.HasPrefix(stdout.String(), want) {
+		t.Fatalf("expected the long description wrapped at 40 columns, got %q", stdout.String())
+	}
+	want = "  --env  The environment to deploy the\n         service to (required)\n"
+	if !strings.Contains(trimTrailingSpaces(stdout.String()), want) {
+		t.Fatalf("expected the flag usage wrapped at 40 columns, got %q", stdout.String())
+	}
+	if strings.Contains(stdout.String(), "\033[") {
+		t.Fatalf("expected no colors when stdout is not a terminal, got %q", stdout.String())
+	}
+
+	tty := &terminalWriter{bytes.NewBuffer(nil)}
+	run(tty, map[string]string{})
+	for _, styled := range []string{styleBold + "Usage:" + styleReset, styleCyan + "--env" + styleReset, styleRed + "(required)" + styleReset} {
+		if !strings.Contains(tty.String(), styled) {
+			t.Fatalf("expected %q in output, got %q", styled, tty.String())
+		}
+	}
+
+	tty.Reset()
+	run(tty, map[string]string{"NO_COLOR": "1"})
+	if strings.Contains(tty.String(), "\033[") {
+		t.Fatalf("expected no colors with NO_COLOR, got %q", tty.String())
+	}
+
+	tty.Reset()
+	run(tty, map[string]string{"NO_COLOR": ""})
+	if !strings.Contains(tty.String(), styleBold+"Usage:"+styleReset) {
+		t.Fatalf("expected colors with an empty NO_COLOR, got %q", tty.String())
+	}
+
+	tty.Reset()
+	run(tty, map[string]string{}, "--no-color")
+	if strings.Contains(tty.String(), "\033[") {
+		t.Fatalf("expected no colors with --no-color, got %q", tty.String())
+	}
+}
+
+func TestIntrospection(t *testing.T) {
+	port := NewIntCmdInput("port").WithDefault(8080).AsFlag().FromEnv([]string{"PORT"}).WithGroup("Networking")
+	name := NewStringCmdInput("name").Required().AsArgument().WithLongDescription("The name of the server")
+	serve := NewCommand("serve", NoOpHook).WithAliases("s").WithFlag(port).WithArgument(name)
+	cli := NewCLI("test", "0.0.1").
+		WithDescription("Test CLI").
+		WithCommand(NewCommand("server", nil).WithChildCommand(serve)).
+		WithCommand(NewCommand("debug", NoOpHook).Hidden())
+
+	if cli.Version() != "0.0.1" || cli.Description() != "Test CLI" || len(cli.Commands()) != 2 {
+		t.Fatalf("unexpected CLI accessors")
+	}
+	if serve.Name() != "serve" || serve.Parent().Name() != "server" || !serve.Runnable() || serve.Parent().Runnable() {
+		t.Fatalf("unexpected command accessors")
+	}
+	if !slices.Equal(serve.Path(), []string{"server", "serve"}) || !slices.Equal(serve.Aliases(), []string{"s"}) {
+		t.Fatalf("unexpected path %v or aliases %v", serve.Path(), serve.Aliases())
+	}
+
+	flag := serve.Flags()[0]
+	if flag.Default() != 8080 || !flag.HasDefault() || flag.IsRequired() || flag.Group() != "Networking" ||
+		!slices.Equal(flag.EnvSources(), []string{"PORT"}) || flag.ValueType().Kind() != reflect.Int {
+		t.Fatalf("unexpected flag accessors")
+	}
+	arg := serve.Arguments()[0]
+	if arg.Default() != nil || arg.HasDefault() || !arg.IsRequired() || arg.LongDescription() != "The name of the server" {
+		t.Fatalf("unexpected argument accessors")
+	}
+
+	visited := []string{}
+	err := cli.Walk(func(path []*Command, cmd *Command) error {
+		names := []string{}
+		for _, c := range path {
+			names = append(names, c.Name())
+		}
+		visited = append(visited, strings.Join(names, " "))
+		if cmd.IsHidden() {
+			return errors.New("hidden")
+		}
+		return nil
+	})
+	if err == nil || err.Error() != "hidden" {
+		t.Fatalf("expected the walk to stop with the error of the visitor, got: %v", err)
+	}
+	if want := []string{"server", "server serve", "debug"}; !slices.Equal(visited, want) {
+		t.Fatalf("expected to visit %v, got %v", want, visited)
+	}
+}
+
+func TestInvocation(t *testing.T) {
+	var invocation *Invocation
+	cli := NewCLI("test", "0.0.1").
+		WithIO(strings.NewReader(""), io.Discard, io.Discard).
+		WithCommand(
+			NewCommand("server", nil).
+				WithChildCommand(
+					NewCommand("serve", func(ctx context.Context, args []string) error {
+						var ok bool
+						invocation, ok = InvocationFromContext(ctx)
+						if !ok {
+							return errors.New("no invocation in context")
+						}
+						return nil
+					}).
+						WithArgument(NewStringCmdInput("name").AsArgument()).
+						WithArgument(NewStringCmdInput("dir").WithDefault(".").AsArgument()).
+						WithFlag(NewCmdSliceInput[string]("tag").WithDefault([]string{}).AsFlag()).
+						WithFlag(NewIntCmdInput("port").WithDefault(8080).AsFlag()).
+						WithFlag(NewBoolCmdInput("verbose").WithDefault(false).AsFlag()),
+				),
+		)
+
+	args := []string{"test", "server", "--tag", "b", "serve", "--port=9090", "web", "--tag=a", "--no-input", "--verbose"}
+	if err := cli.Run(context.Background(), args); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if invocation.Command.Name() != "serve" || !slices.Equal(invocation.Path, []string{"server", "serve"}) {
+		t.Fatalf("unexpected command %s at %v", invocation.Command.Name(), invocation.Path)
+	}
+	if !slices.Equal(invocation.Args, args) {
+		t.Fatalf("expected raw args %v, got %v", args, invocation.Args)
+	}
+	wantFlags := []FlagValue{{"tag", "b"}, {"port", "9090"}, {"tag", "a"}, {"verbose", ""}}
+	if !slices.Equal(invocation.Flags, wantFlags) {
+		t.Fatalf("expected flags %v, got %v", wantFlags, invocation.Flags)
+	}
+	if !slices.Equal(invocation.Positionals, []string{"web"}) {
+		t.Fatalf("expected positionals [web], got %v", invocation.Positionals)
+	}
+	if !slices.Equal(invocation.FlagValues("tag"), []string{"b", "a"}) {
+		t.Fatalf("expected tag values [b a], got %v", invocation.FlagValues("tag"))
+	}
+	for name, want := range map[string]bool{"name": true, "dir": false, "port": true, "verbose": true, "no-input": false} {
+		if got := invocation.IsSet(name); got != want {
+			t.Fatalf("expected IsSet(%s) to be %v, got %v", name, want, got)
+		}
+	}
+}
+
+func TestArgumentOrder(t *testing.T) {
+	type params struct {
+		Name    string   `cling-name:"name"`
+		Tags    []string `cling-name:"tag"`
+		Port    int      `cling-name:"port"`
+		Verbose bool     `cling-name:"verbose"`
+	}
+	var got []string
+	var hydrated params
+	cli := NewCLI("test", "0.0.1").
+		WithIO(strings.NewReader(""), io.Discard, io.Discard).
+		WithCommand(
+			NewCommand("server", nil).
+				WithChildCommand(
+					NewCommand("serve", func(ctx context.Context, args []string) error {
+						got = args
+						return Hydrate(ctx, args, &hydrated)
+					}).
+						WithArgument(NewStringCmdInput("name").AsArgument()).
+						WithFlag(NewCmdSliceInput[string]("tag").WithDefault([]string{}).AsFlag()).
+						WithFlag(NewIntCmdInput("port").WithDefault(8080).AsFlag()).
+						WithFlag(NewBoolCmdInput("verbose").WithDefault(false).AsFlag()),
+				),
+		)
+
+	want := []string{"--tag", "b", "--port=9090", "web", "--tag=a", "--no-input", "--verbose"}
+	for i := 0; i < 10; i++ {
+		args := []string{"test", "server", "--tag", "b", "serve", "--port=9090", "web", "--tag=a", "--no-input", "--verbose"}
+		if err := cli.Run(context.Background(), args); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(got, want) {
+			t.Fatalf("expected args %q, got %q", want, got)
+		}
+	}
+	if hydrated.Name != "web" || !slices.Equal(hydrated.Tags, []string{"b", "a"}) || hydrated.Port != 9090 || !hydrated.Verbose {
+		t.Fatalf("unexpected hydrated values %+v", hydrated)
+	}
+}
+
+func TestHookContext(t *testing.T) {
+	type params struct {
+		Name string `cling-name:"name"`
+	}
+	actionErr := errors.New("action failed")
+	calls := []string{}
+	var seen HookContext
+	record := func(name string) CommandHook {
+		return func(ctx context.Context, args []string) error {
+			calls = append(calls, name)
+			hookContext, ok := HookContextFromContext(ctx)
+			if !ok {
+				return errors.New("no hook context in context")
+			}
+			seen = *hookContext
+			return nil
+		}
+	}
+	cli := NewCLI("test", "0.0.1").
+		WithIO(strings.NewReader(""), io.Discard, io.Discard).
+		WithPostRun(record("cli post-run")).
+		WithCommand(
+			NewCommand("server", nil).
+				WithPersistentPostRun(record("server persistent post-run")).
+				WithChildCommand(
+					NewCommand("serve", func(ctx context.Context, args []string) error {
+						if err := Hydrate(ctx, args, &params{}); err != nil {
+							return err
+						}
+						return actionErr
+					}).
+						WithArgument(NewStringCmdInput("name").AsArgument()).
+						WithPostRun(record("serve post-run")),
+				),
+		)
+
+	err := cli.Run(context.Background(), []string{"test", "server", "serve", "web"})
+	if !errors.Is(err, actionErr) {
+		t.Fatalf("expected the action error, got %v", err)
+	}
+	wantCalls := []string{"serve post-run", "server persistent post-run", "cli post-run"}
+	if !slices.Equal(calls, wantCalls) {
+		t.Fatalf("expected hooks %v to run, got %v", wantCalls, calls)
+	}
+	if seen.Command.Name() != "serve" || !slices.Equal(seen.Invocation.Positionals, []string{"web"}) {
+		t.Fatalf("unexpected command %s with positionals %v", seen.Command.Name(), seen.Invocation.Positionals)
+	}
+	if !errors.Is(seen.Err, actionErr) {
+		t.Fatalf("expected hooks to see the action error, got %v", seen.Err)
+	}
+	if dest, ok := seen.Destination.(*params); !ok || dest.Name != "web" {
+		t.Fatalf("expected the hydrated destination, got %#v", seen.Destination)
+	}
+	if seen.Duration <= 0 {
+		t.Fatalf("expected the duration of the action, got %v", seen.Duration)
+	}
+}
+
+func TestPostRunErrorKeepsExitCode(t *testing.T) {
+	hookErr := errors.New("hook failed")
+	cli := NewCLI("test", "0.0.1").
+		WithIO(strings.NewReader(""), io.Discard, io.Discard).
+		WithCommand(
+			NewCommand("fail", func(ctx context.Context, args []string) error {
+				return NewExitCoder(errors.New("action failed"), 3).(error)
+			}).WithPostRun(func(ctx context.Context, args []string) error { return hookErr }),
+		)
+
+	err := cli.Run(context.Background(), []string{"test", "fail"})
+	if !errors.Is(err, hookErr) {
+		t.Fatalf("expected the hook error, got %v", err)
+	}
+	if code := exitCode(err); code != 3 {
+		t.Fatalf("expected exit code 3, got %d", code)
+	}
+}
+
+func TestPostRunErrors(t *testing.T) {
+	hookErr := errors.New("hook failed")
+	ran := false
+	cli := NewCLI("test", "0.0.1").
+		WithIO(strings.NewReader(""), io.Discard, io.Discard).
+		WithCommand(
+			NewCommand("parent", nil).
+				WithPersistentPostRun(func(ctx context.Context, args []string) error {
+					ran = true
+					return nil
+				}).
+				WithChildCommand(
+					NewCommand("child", NoOpHook).
+						WithPostRun(func(ctx context.Context, args []string) error { return hookErr }),
+				),
+		)
+
+	err := cli.Run(context.Background(), []string{"test", "parent", "child"})
+	if !errors.Is(err, hookErr) {
+		t.Fatalf("expected the hook error, got %v", err)
+	}
+	if !ran {
+		t.Fatal("expected the persistent post-run hook to run after a failing post-run hook")
+	}
+}
+
+func TestPostRunAfterPanic(t *testing.T) {
+	ran := []string{}
+	hook := func(name string) CommandHook {
+		return func(ctx context.Context, args []string) error {
+			if hookContext, ok := HookContextFromContext(ctx); ok && errors.Is(hookContext.Err, ErrPanic) {
+				ran = append(ran, name)
+			}
+			return nil
+		}
+	}
+	cli := NewCLI("test", "0.0.1").
+		WithIO(strings.NewReader(""), io.Discard, io.Discard).
+		WithPostRun(hook("cli")).
+		WithCommand(
+			NewCommand("parent", nil).
+				WithPersistentPostRun(hook("parent")).
+				WithChildCommand(
+					NewCommand("child", func(ctx context.Context, args []string) error {
+						panic("something broke")
+					}).WithPostRun(hook("child")),
+				),
+		)
+
+	defer func() {
+		if r := recover(); r != "something broke" {
+			t.Fatalf("expected the panic to be passed on, got %v", r)
+		}
+		if want := []string{"child", "parent", "cli"}; !reflect.DeepEqual(ran, want) {
+			t.Fatalf("expected the post-run hooks %v to see the panic, got %v", want, ran)
+		}
+	}()
+	_ = cli.Run(context.Background(), []string{"test", "parent", "child"})
+	t.Fatal("expected the panic to be passed on")
+}
+
+func TestHelpConstraints(t *testing.T) {
+	stdout := bytes.NewBuffer(nil)
+	cli := NewCLI("test", "0.0.1").
+		WithIO(strings.NewReader(""), stdout, io.Discard).
+		WithCommand(
+			NewCommand("scale", action).
+				WithArgument(
+					NewStringCmdInput("cluster").
+						WithValidator(NewRegexValidator(`^[a-z]+$`)).
+						WithDescription("Cluster to scale").
+						Required().
+						AsArgument(),
+				).
+				WithArgument(
+					NewIntCmdInput("replicas").
+						WithValidator(NewIntRangeValidator(1, 10)).
+						WithDefault(3).
+						WithDescription("Number of replicas").
+						AsArgument(),
+				).
+				WithFlag(
+					NewStringCmdInput("mode").
+						WithValidator(NewEnumValidator("fast", "safe")).
+						WithDefault("safe").
+						WithDescription("Scaling mode").
+						AsFlag(),
+				),
+		)
+
+	if err := cli.Run(context.Background(), []string{"test", "scale", "--help"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"<cluster>   Cluster to scale (matching ^[a-z]+$)",
+		"[replicas]  Number of replicas (between 1 and 10) (default: 3)",
+		"--mode  Scaling mode (one of fast|safe) (default: safe)",
+	} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Fatalf("expected help to contain %q, got:\n%s", want, stdout.String())
+		}
+	}
+}
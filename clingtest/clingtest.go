@@ -0,0 +1,132 @@
+// Package clingtest runs CLIng applications in-process for testing.
+package clingtest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/binaek/cling"
+)
+
+// Result is the outcome of running a CLI.
+type Result struct {
+	// Stdout is everything the CLI wrote to its stdout.
+	Stdout string
+	// Stderr is everything the CLI wrote to its stderr.
+	Stderr string
+	// ExitCode is the code the process would have exited with.
+	ExitCode int
+	// Err is the error returned by the CLI.
+	Err error
+}
+
+type options struct {
+	ctx   context.Context
+	env   map[string]string
+	stdin string
+	tty   bool
+}
+
+// Option configures a run.
+type Option func(*options)
+
+// WithContext sets the context the CLI runs with.
+func WithContext(ctx context.Context) Option {
+	return func(o *options) {
+		o.ctx = ctx
+	}
+}
+
+// WithEnv sets the environment visible to the CLI.
+// The process environment is never visible to the CLI.
+func WithEnv(env map[string]string) Option {
+	return func(o *options) {
+		o.env = env
+	}
+}
+
+// WithStdin sets the content of stdin.
+func WithStdin(stdin string) Option {
+	return func(o *options) {
+		o.stdin = stdin
+	}
+}
+
+// WithTTY makes stdin behave like an interactive terminal, enabling prompts and confirmations.
+func WithTTY() Option {
+	return func(o *options) {
+		o.tty = true
+	}
+}
+
+// Run splits the command line like a shell would and runs the CLI with it.
+// The command line must not include the name of the CLI.
+// The streams and the environment lookup of the CLI are replaced for the run and restored after it.
+func Run(cli *cling.CLI, cmdLine string, opts ...Option) *Result {
+	args, err := Split(cmdLine)
+	if err != nil {
+		return &Result{ExitCode: 1, Err: err}
+	}
+	return RunArgs(cli, args, opts...)
+}
+
+// RunArgs is like Run, but takes already split arguments.
+func RunArgs(cli *cling.CLI, args []string, opts ...Option) *Result {
+	o := &options{
+		ctx: context.Background(),
+		env: map[string]string{},
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	var stdin io.Reader = strings.NewReader(o.stdin)
+	if o.tty {
+		stdin = &terminal{Reader: stdin}
+	}
+	stdout := bytes.NewBuffer(nil)
+	stderr := bytes.NewBuffer(nil)
+
+	// restore the settings of the CLI, so that it can be reused
+	prevIn, prevOut, prevErr := cli.IO()
+	prevLookup := cli.EnvLookup()
+	defer func() {
+		cli.WithIO(prevIn, prevOut, prevErr).WithEnvLookup(prevLookup)
+	}()
+
+	cli.WithIO(stdin, stdout, stderr).
+		WithEnvLookup(func(key string) (string, bool) {
+			val, ok := o.env[key]
+			return val, ok
+		})
+
+	err := cli.Run(o.ctx, append([]string{cli.Name()}, args...))
+	return &Result{
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+		ExitCode: exitCode(err),
+		Err:      err,
+	}
+}
+
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitCoder cling.ExitCoder
+	if errors.As(err, &exitCoder) {
+		return exitCoder.ExitCode()
+	}
+	return 1
+}
+
+type terminal struct {
+	io.Reader
+}
+
+func (t *terminal) IsTerminal() bool {
+	return true
+}
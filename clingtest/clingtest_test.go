@@ -0,0 +1,131 @@
+package clingtest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/binaek/cling"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		cmdLine string
+		want    []string
+		wantErr bool
+	}{
+		{`cmd --flag x`, []string{"cmd", "--flag", "x"}, false},
+		{`  cmd   spaced  `, []string{"cmd", "spaced"}, false},
+		{`cmd 'single quoted' "double quoted"`, []string{"cmd", "single quoted", "double quoted"}, false},
+		{`cmd --name="a \"b\" c"`, []string{"cmd", `--name=a "b" c`}, false},
+		{`cmd 'it\s' "\n"`, []string{"cmd", `it\s`, `\n`}, false},
+		{`cmd escaped\ space ''`, []string{"cmd", "escaped space", ""}, false},
+		{`cmd 'unterminated`, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cmdLine, func(t *testing.T) {
+			got, err := Split(tt.cmdLine)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error: %v, got: %v", tt.wantErr, err)
+			}
+			if err == nil && !slices.Equal(got, tt.want) {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+type greetConfig struct {
+	Name     string `cling-name:"name"`
+	Greeting string `cling-name:"greeting"`
+}
+
+func newTestCLI() *cling.CLI {
+	return cling.NewCLI("greeter", "1.0.0").
+		WithDescription("Greets people").
+		WithCommand(
+			cling.NewCommand("greet", func(ctx context.Context, args []string) error {
+				cfg := &greetConfig{}
+				if err := cling.Hydrate(ctx, args, cfg); err != nil {
+					return err
+				}
+				if cfg.Name == "nobody" {
+					return cling.NewExitCoder(errors.New("nobody to greet"), 3).(error)
+				}
+				_, err := fmt.Fprintf(cling.Stdout(ctx), "%s, %s!\n", cfg.Greeting, cfg.Name)
+				return err
+			}).
+				WithDescription("Greet someone").
+				WithArgument(cling.NewStringCmdInput("name").WithDescription("Who to greet").Required().AsArgument()).
+				WithFlag(
+					cling.NewStringCmdInput("greeting").
+						WithDefault("Hello").
+						WithDescription("The greeting to use").
+						AsFlag().
+						FromEnv([]string{"GREETING"}),
+				),
+		).
+		WithCommand(
+			cling.NewCommand("cat", func(ctx context.Context, args []string) error {
+				_, err := io.Copy(cling.Stdout(ctx), cling.Stdin(ctx))
+				return err
+			}),
+		)
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		cmdLine  string
+		opts     []Option
+		stdout   string
+		exitCode int
+	}{
+		{"flag", `greet "Jane Doe" --greeting Hi`, nil, "Hi, Jane Doe!\n", 0},
+		{"default", `greet world`, nil, "Hello, world!\n", 0},
+		{"env", `greet world`, []Option{WithEnv(map[string]string{"GREETING": "Hey"})}, "Hey, world!\n", 0},
+		{"stdin", `cat`, []Option{WithStdin("piped")}, "piped", 0},
+		{"exit coder", `greet nobody`, nil, "", 3},
+		{"error", `greet`, nil, "", 1},
+		{"bad command line", `greet 'world`, nil, "", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := Run(newTestCLI(), tt.cmdLine, tt.opts...)
+			if res.ExitCode != tt.exitCode {
+				t.Fatalf("expected exit code %d, got %d (err: %v)", tt.exitCode, res.ExitCode, res.Err)
+			}
+			if res.Stdout != tt.stdout {
+				t.Fatalf("expected stdout %q, got %q", tt.stdout, res.Stdout)
+			}
+		})
+	}
+}
+
+func TestRunRestoresCLI(t *testing.T) {
+	stdout := bytes.NewBuffer(nil)
+	cli := newTestCLI().
+		WithIO(strings.NewReader(""), stdout, io.Discard).
+		WithEnvLookup(func(key string) (string, bool) { return "Howdy", key == "GREETING" })
+
+	if res := Run(cli, `greet test`); res.Stdout != "Hello, test!\n" {
+		t.Fatalf("expected the output of the run, got %q (err: %v)", res.Stdout, res.Err)
+	}
+	if err := cli.Run(context.Background(), []string{"greeter", "greet", "world"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stdout.String() != "Howdy, world!\n" {
+		t.Fatalf("expected the streams and environment of the CLI to be restored, got %q", stdout.String())
+	}
+}
+
+func TestHelpGolden(t *testing.T) {
+	AssertHelpGolden(t, newTestCLI(), "greet", "testdata/greet_help.golden")
+}
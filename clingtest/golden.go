@@ -0,0 +1,44 @@
+package clingtest
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/binaek/cling"
+)
+
+var update = flag.Bool("clingtest.update", false, "update golden files instead of comparing against them")
+
+// AssertGolden compares got with the content of the golden file at path.
+// Run the tests with -clingtest.update to write got to the golden file instead.
+func AssertGolden(t testing.TB, path string, got string) {
+	t.Helper()
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("could not create golden directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("could not write golden file: %v", err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("could not read golden file (run with -clingtest.update to create it): %v", err)
+	}
+	if string(want) != got {
+		t.Errorf("output does not match golden file %s\n--- want\n%s\n--- got\n%s", path, want, got)
+	}
+}
+
+// AssertHelpGolden runs the command line with --help and compares the help output with the golden file at path.
+func AssertHelpGolden(t testing.TB, cli *cling.CLI, cmdLine string, path string, opts ...Option) {
+	t.Helper()
+	res := Run(cli, cmdLine+" --help", opts...)
+	if res.Err != nil {
+		t.Fatalf("unexpected error: %v", res.Err)
+	}
+	AssertGolden(t, path, res.Stdout)
+}
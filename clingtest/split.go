@@ -0,0 +1,11 @@
+package clingtest
+
+import "github.com/binaek/cling"
+
+var ErrUnterminatedQuote = cling.ErrUnterminatedQuote
+
+// Split splits a command line into arguments the way a POSIX shell would.
+// See cling.SplitCommandLine.
+func Split(cmdLine string) ([]string, error) {
+	return cling.SplitCommandLine(cmdLine)
+}
@@ -1,5 +1,7 @@
 package cling
 
+import "reflect"
+
 type CmdInput interface {
 	// Name returns the name of the command input.
 	Name() string
@@ -13,23 +15,46 @@ type CmdInput interface {
 	AsFlag() CmdFlag
 	// AsArgument returns the command input as an argument.
 	AsArgument() CmdArg
-
-	isRequired() bool
-	hasDefault() bool
-	getDefault() any
+	// ValueType returns the type of the value of the command input, like string or []int.
+	ValueType() reflect.Type
+	// IsRequired reports whether the command input is required.
+	IsRequired() bool
+	// HasDefault reports whether the command input has a default value.
+	HasDefault() bool
+	// Default returns the default value of the command input, or nil if it has none.
+	Default() any
 }
 
 type ValidatorProvider interface {
 	getValidator() validatorAny
 }
 
+type elementValidatorProvider interface {
+	getElementValidator() validatorAny
+}
+
+type TransformerProvider interface {
+	getTransformer() transformerAny
+}
+
 type CmdInputWithDefaultAndValidator[S any] interface {
 	CmdInput
 	ValidatorProvider
+	TransformerProvider
 	// WithDefault sets the default value of the command input.
 	WithDefault(value S) CmdInputWithDefaultAndValidator[S]
 	// WithValidator sets the validator of the command input.
 	WithValidator(validator Validator[S]) CmdInputWithDefaultAndValidator[S]
+	// WithTransform adds a transform to the command input.
+	// Transforms run in the order they are added, after parsing and before validation.
+	WithTransform(transform Transformer[S]) CmdInputWithDefaultAndValidator[S]
+}
+
+type CmdSliceInput[E any] interface {
+	CmdInputWithDefaultAndValidator[[]E]
+	// WithElementValidator sets the validator that is run against every element of the command input.
+	// Element validators run before the validator set by WithValidator.
+	WithElementValidator(validator Validator[E]) CmdSliceInput[E]
 }
 
 type CmdFlag interface {
@@ -39,12 +64,18 @@ type CmdFlag interface {
 	// The default value is the first non-empty value found in the environment.
 	// This will override the default value set by WithDefault.
 	FromEnv([]string) CmdFlag
-	envSources() []string
+	// WithGroup sets the title of the group the flag is listed under in the help.
+	WithGroup(title string) CmdFlag
+	// EnvSources returns the environment variables the flag is read from.
+	EnvSources() []string
+	// Group returns the title of the group the flag is listed under in the help, or an empty string.
+	Group() string
 }
 
 type CmdArg interface {
 	CmdInput
 	// WithLongDescription sets the long description of the command argument.
 	WithLongDescription(string) CmdArg
-	longDescription() string
+	// LongDescription returns the long description of the command argument.
+	LongDescription() string
 }
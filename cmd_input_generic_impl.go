@@ -1,5 +1,7 @@
 package cling
 
+import "reflect"
+
 // NewIntCmdInput creates a new integer command input with the given name.
 func NewIntCmdInput(name string) CmdInputWithDefaultAndValidator[int] {
 	return newGenericCmdInput[int](name)
@@ -22,7 +24,9 @@ type genericCmdInput[T int | string | bool] struct {
 	description  string
 	lDescription string
 	envs         []string
+	groupTitle   string
 	validator    validatorAny
+	transforms   []Transformer[T]
 }
 
 func newGenericCmdInput[T int | string | bool](name string) CmdInputWithDefaultAndValidator[T] {
@@ -36,10 +40,19 @@ func (f *genericCmdInput[T]) FromEnv(sources []string) CmdFlag {
 	return f
 }
 
-func (f *genericCmdInput[T]) envSources() []string {
+func (f *genericCmdInput[T]) EnvSources() []string {
 	return f.envs
 }
 
+func (f *genericCmdInput[T]) WithGroup(title string) CmdFlag {
+	f.groupTitle = title
+	return f
+}
+
+func (f *genericCmdInput[T]) Group() string {
+	return f.groupTitle
+}
+
 func (f *genericCmdInput[T]) Description() string {
 	return f.description
 }
@@ -49,7 +62,7 @@ func (f *genericCmdInput[T]) WithDescription(value string) CmdInput {
 	return f
 }
 
-func (f *genericCmdInput[T]) longDescription() string {
+func (f *genericCmdInput[T]) LongDescription() string {
 	return f.lDescription
 }
 
@@ -84,6 +97,18 @@ func (f *genericCmdInput[T]) getValidator() validatorAny {
 	return f.validator
 }
 
+func (f *genericCmdInput[T]) WithTransform(transform Transformer[T]) CmdInputWithDefaultAndValidator[T] {
+	f.transforms = append(f.transforms, transform)
+	return f
+}
+
+func (f *genericCmdInput[T]) getTransformer() transformerAny {
+	if len(f.transforms) == 0 {
+		return nil
+	}
+	return &genericTransformerWrapper[T]{transforms: f.transforms}
+}
+
 func (f *genericCmdInput[T]) AsFlag() CmdFlag {
 	return f
 }
@@ -92,17 +117,21 @@ func (f *genericCmdInput[T]) AsArgument() CmdArg {
 	return f
 }
 
-func (f *genericCmdInput[T]) isRequired() bool {
+func (f *genericCmdInput[T]) ValueType() reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+func (f *genericCmdInput[T]) IsRequired() bool {
 	return f.required
 }
 
-func (f *genericCmdInput[T]) hasDefault() bool {
+func (f *genericCmdInput[T]) HasDefault() bool {
 	return f.defaultValue != nil
 }
 
-func (f *genericCmdInput[T]) getDefault() any {
+func (f *genericCmdInput[T]) Default() any {
 	if f.defaultValue != nil {
 		return *f.defaultValue
 	}
-	return 0
+	return nil
 }
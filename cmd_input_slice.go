@@ -1,16 +1,21 @@
 package cling
 
-type cmdInputGenericSlice[T comparable] struct {
-	name         string
-	description  string
-	lDescription string
-	defaultValue []T
-	required     bool
-	envs         []string
-	validator    validatorAny
-}
+import "reflect"
 
-func NewCmdSliceInput[T comparable](name string) CmdInputWithDefaultAndValidator[[]T] {
+type cmdInputGenericSlice[T comparable] struct {
+	name          string
+	description   string
+	lDescription  string
+	defaultValue  []T
+	required      bool
+	envs          []string
+	groupTitle    string
+	validator     validatorAny
+	elemValidator validatorAny
+	transforms    []Transformer[[]T]
+}
+
+func NewCmdSliceInput[T comparable](name string) CmdSliceInput[T] {
 	return &cmdInputGenericSlice[T]{
 		name:         name,
 		defaultValue: nil,
@@ -53,6 +58,16 @@ func (f *cmdInputGenericSlice[T]) WithValidator(validator Validator[[]T]) CmdInp
 	return f
 }
 
+func (f *cmdInputGenericSlice[T]) WithElementValidator(validator Validator[T]) CmdSliceInput[T] {
+	f.elemValidator = &genericValidatorWrapper[T]{validator: validator}
+	return f
+}
+
+func (f *cmdInputGenericSlice[T]) WithTransform(transform Transformer[[]T]) CmdInputWithDefaultAndValidator[[]T] {
+	f.transforms = append(f.transforms, transform)
+	return f
+}
+
 func (f *cmdInputGenericSlice[T]) FromEnv(sources []string) CmdFlag {
 	f.envs = sources
 	return f
@@ -67,22 +82,49 @@ func (f *cmdInputGenericSlice[T]) getValidator() validatorAny {
 	return f.validator
 }
 
-func (f *cmdInputGenericSlice[T]) envSources() []string {
+func (f *cmdInputGenericSlice[T]) getElementValidator() validatorAny {
+	return f.elemValidator
+}
+
+func (f *cmdInputGenericSlice[T]) getTransformer() transformerAny {
+	if len(f.transforms) == 0 {
+		return nil
+	}
+	return &genericTransformerWrapper[[]T]{transforms: f.transforms}
+}
+
+func (f *cmdInputGenericSlice[T]) EnvSources() []string {
 	return f.envs
 }
 
-func (f *cmdInputGenericSlice[T]) longDescription() string {
+func (f *cmdInputGenericSlice[T]) WithGroup(title string) CmdFlag {
+	f.groupTitle = title
+	return f
+}
+
+func (f *cmdInputGenericSlice[T]) Group() string {
+	return f.groupTitle
+}
+
+func (f *cmdInputGenericSlice[T]) LongDescription() string {
 	return f.lDescription
 }
 
-func (f *cmdInputGenericSlice[T]) hasDefault() bool {
+func (f *cmdInputGenericSlice[T]) HasDefault() bool {
 	return f.defaultValue != nil
 }
 
-func (f *cmdInputGenericSlice[T]) getDefault() any {
+func (f *cmdInputGenericSlice[T]) Default() any {
+	if f.defaultValue == nil {
+		return nil
+	}
 	return f.defaultValue
 }
 
-func (f *cmdInputGenericSlice[T]) isRequired() bool {
+func (f *cmdInputGenericSlice[T]) ValueType() reflect.Type {
+	return reflect.TypeFor[[]T]()
+}
+
+func (f *cmdInputGenericSlice[T]) IsRequired() bool {
 	return f.required
 }
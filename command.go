@@ -2,7 +2,9 @@ package cling
 
 import (
 	"context"
+	stdErrs "errors"
 	"slices"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -16,6 +18,7 @@ func NoOpHook(ctx context.Context, args []string) error {
 
 type Command struct {
 	name            string
+	aliases         []string
 	description     string
 	longDescription string
 	action          CommandHandler
@@ -23,12 +26,19 @@ type Command struct {
 	arguments       []CmdArg
 	children        []*Command
 	parent          *Command
+	confirmation    string
+	examples        []*example
+	seeAlso         []string
+	group           string
+	hidden          bool
 
 	// hooks
 	preRun            CommandHook
 	postRun           CommandHook
 	persistentPreRun  CommandHook
 	persistentPostRun CommandHook
+
+	middlewares []Middleware
 }
 
 func NewCommand(name string, action CommandHandler) *Command {
@@ -65,6 +75,58 @@ func (c *Command) WithPersistentPostRun(hook CommandHook) *Command {
 	return c
 }
 
+// Use adds middlewares that wrap the action of the command and of all of its children.
+// The middlewares of a parent run outside those of its children, in the order they were added.
+func (c *Command) Use(middlewares ...Middleware) *Command {
+	c.middlewares = append(c.middlewares, middlewares...)
+	return c
+}
+
+// WithAliases sets alternative names the command can be invoked with.
+func (c *Command) WithAliases(aliases ...string) *Command {
+	c.aliases = aliases
+	return c
+}
+
+// hasName reports whether the command is invoked with the given name.
+func (c *Command) hasName(name string) bool {
+	return c.name == name || slices.Contains(c.aliases, name)
+}
+
+// WithExample adds an example to the help of the command.
+// The command line starts with the name of the CLI, like 'mycli deploy --env prod'.
+func (c *Command) WithExample(description string, commandLine string) *Command {
+	c.examples = append(c.examples, &example{description: description, commandLine: commandLine})
+	return c
+}
+
+// WithSeeAlso adds references to related commands to the help of the command.
+// Paths are the names of the commands from the top level command down, like 'config set'.
+func (c *Command) WithSeeAlso(paths ...string) *Command {
+	c.seeAlso = append(c.seeAlso, paths...)
+	return c
+}
+
+// WithGroup sets the title of the group the command is listed under in the help of its parent.
+func (c *Command) WithGroup(title string) *Command {
+	c.group = title
+	return c
+}
+
+// Hidden hides the command from the commands listed in the usage, the help and generated documentation.
+// A hidden command can still be run.
+func (c *Command) Hidden() *Command {
+	c.hidden = true
+	return c
+}
+
+// visibleCommands returns the commands that are not hidden.
+func visibleCommands(commands []*Command) []*Command {
+	return slices.DeleteFunc(slices.Clone(commands), func(cmd *Command) bool {
+		return cmd.hidden
+	})
+}
+
 func (c *Command) WithDescription(description string) *Command {
 	c.description = description
 	return c
@@ -81,6 +143,19 @@ func (command *Command) WithChildCommand(cmd *Command) *Command {
 	return command
 }
 
+// WithConfirmation makes the command ask for confirmation with the given message before it runs.
+// A --yes (-y) flag is added to the command to skip the confirmation.
+// When stdin is not an interactive terminal, the command refuses to run unless --yes is passed.
+func (command *Command) WithConfirmation(message string) *Command {
+	command.confirmation = message
+	return command.WithFlag(
+		NewBoolCmdInput(confirmationFlag).
+			WithDefault(false).
+			WithDescription("Skip the confirmation prompt").
+			AsFlag(),
+	)
+}
+
 func (command *Command) WithFlag(flag CmdFlag) *Command {
 	command.flags = append(command.flags, flag)
 	return command
@@ -91,20 +166,104 @@ func (command *Command) WithArgument(arg CmdArg) *Command {
 	return command
 }
 
-func (command *Command) execute(ctx context.Context, args []string) error {
+// Name returns the name of the command.
+func (c *Command) Name() string {
+	return c.name
+}
+
+// Aliases returns the alternative names of the command.
+func (c *Command) Aliases() []string {
+	return slices.Clone(c.aliases)
+}
+
+// Description returns the description of the command.
+func (c *Command) Description() string {
+	return c.description
+}
+
+// LongDescription returns the long description of the command.
+func (c *Command) LongDescription() string {
+	return c.longDescription
+}
+
+// Group returns the title of the group the command is listed under in the help, or an empty string.
+func (c *Command) Group() string {
+	return c.group
+}
+
+// IsHidden reports whether the command is hidden from the help and generated documentation.
+func (c *Command) IsHidden() bool {
+	return c.hidden
+}
+
+// Runnable reports whether the command has an action, rather than only grouping its children.
+func (c *Command) Runnable() bool {
+	return c.action != nil
+}
+
+// Flags returns the flags of the command.
+func (c *Command) Flags() []CmdFlag {
+	return slices.Clone(c.flags)
+}
+
+// Arguments returns the arguments of the command, in order.
+func (c *Command) Arguments() []CmdArg {
+	return slices.Clone(c.arguments)
+}
+
+// Children returns the child commands of the command.
+func (c *Command) Children() []*Command {
+	return slices.Clone(c.children)
+}
+
+// Parent returns the parent of the command, or nil for a top level command.
+func (c *Command) Parent() *Command {
+	return c.parent
+}
+
+// Path returns the names of the commands from the top level command down to this one.
+func (c *Command) Path() []string {
+	return c.commandPath()
+}
+
+// acceptsArguments reports whether the command can take positionals that are not the names of its children.
+func (command *Command) acceptsArguments() bool {
+	return command.action != nil && len(command.arguments) > 0
+}
+
+func (command *Command) execute(ctx context.Context, args []string, middlewares []Middleware) (err error) {
 	if command.action == nil {
 		return errors.Wrapf(ErrInvalidCommand, "command '%s' has no action", command.name)
 	}
-	if err := command.executePrerun(ctx, args); err != nil {
-		return err
+	hookContext, ok := HookContextFromContext(ctx)
+	if !ok {
+		hookContext = &HookContext{Command: command}
 	}
-	if err := command.action(ctx, args); err != nil {
-		return err
-	}
-	if err := command.executePostRun(ctx, args); err != nil {
+
+	// post-run hooks run even if the command failed or panicked, so that they can clean up
+	var start time.Time
+	defer func() {
+		r := recover()
+		hookContext.Err = err
+		if r != nil {
+			hookContext.Err = errors.Wrapf(ErrPanic, "%v", r)
+		}
+		if !start.IsZero() {
+			hookContext.Duration = time.Since(start)
+		}
+		if postErr := command.executePostRun(ctx, args); postErr != nil {
+			err = stdErrs.Join(err, postErr)
+		}
+		if r != nil {
+			panic(r)
+		}
+	}()
+
+	if err = command.executePrerun(ctx, args); err != nil {
 		return err
 	}
-	return nil
+	start = time.Now()
+	return command.wrapAction(middlewares)(ctx, args)
 }
 
 func (command *Command) executePrerun(ctx context.Context, args []string) error {
@@ -125,21 +284,19 @@ func (command *Command) executePrerun(ctx context.Context, args []string) error
 	return nil
 }
 
+// executePostRun runs all post-run hooks, even if some of them fail, and joins their errors.
 func (command *Command) executePostRun(ctx context.Context, args []string) error {
+	errs := []error{}
 	if command.postRun != nil {
-		if err := command.postRun(ctx, args); err != nil {
-			return err
-		}
+		errs = append(errs, command.postRun(ctx, args))
 	}
 	path := command.pathToRoot()
 	for _, cmd := range path {
 		if cmd.persistentPostRun != nil {
-			if err := cmd.persistentPostRun(ctx, args); err != nil {
-				return err
-			}
+			errs = append(errs, cmd.persistentPostRun(ctx, args))
 		}
 	}
-	return nil
+	return stdErrs.Join(errs...)
 }
 
 func (c *Command) pathToRoot() []*Command {
@@ -167,6 +324,9 @@ func (c *Command) validate() error {
 	if err := c.validateFlagsAndArgs(); err != nil {
 		return err
 	}
+	if err := validateCommandNames(c.children); err != nil {
+		return err
+	}
 	for _, child := range c.children {
 		if err := child.validate(); err != nil {
 			return err
@@ -175,6 +335,21 @@ func (c *Command) validate() error {
 	return nil
 }
 
+// validateCommandNames checks that the names and aliases of sibling commands do not clash,
+// as only the first command with a name could ever run.
+func validateCommandNames(commands []*Command) error {
+	seen := map[string]string{}
+	for _, cmd := range commands {
+		for _, name := range append([]string{cmd.name}, cmd.aliases...) {
+			if other, ok := seen[name]; ok {
+				return errors.Wrapf(ErrInvalidCLIngConfig, "'%s' of command '%s' is already used by command '%s'", name, cmd.name, other)
+			}
+			seen[name] = cmd.name
+		}
+	}
+	return nil
+}
+
 func (c *Command) validateFlagsAndArgs() error {
 	// also validate that there's no conflict on the names across flags and arguments
 	flagAndArgNames := make([]string, 0, len(c.flags))
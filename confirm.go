@@ -0,0 +1,48 @@
+package cling
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var ErrNotConfirmed = errors.New("command not confirmed")
+
+const confirmationFlag = "yes"
+
+// confirm asks for confirmation if the command requires it.
+func (c *CLI) confirm(command *Command, flags map[string][]string, p *prompter, noInput bool) error {
+	if command.confirmation == "" {
+		return nil
+	}
+	if values, ok := flags[confirmationFlag]; ok {
+		yes, err := strconv.ParseBool(values[len(values)-1])
+		if values[len(values)-1] == "" || (err == nil && yes) {
+			return nil
+		}
+	}
+	if noInput || !isTerminal(c.stdin) {
+		return errors.Wrapf(ErrNotConfirmed, "refusing to run '%s' without confirmation - pass --%s to proceed", command.name, confirmationFlag)
+	}
+	confirmed, err := p.confirm(command.confirmation)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return errors.Wrapf(ErrNotConfirmed, "'%s' aborted", command.name)
+	}
+	return nil
+}
+
+// confirm asks a yes/no question. Anything but an explicit yes is a no.
+func (p *prompter) confirm(message string) (bool, error) {
+	fmt.Fprintf(p.out, "%s [y/N]: ", message)
+	answer, err := p.readLine()
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains([]string{"y", "yes"}, strings.ToLower(strings.TrimSpace(answer))), nil
+}
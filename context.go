@@ -1,13 +1,28 @@
 package cling
 
-import "context"
+import (
+	"context"
+	"io"
+	"os"
+)
 
 type ClingContextKey string
 
 const (
-	ContextKeyCommand ClingContextKey = "command"
+	ContextKeyCommand    ClingContextKey = "command"
+	ContextKeyPrompter   ClingContextKey = "prompter"
+	ContextKeyIO         ClingContextKey = "io"
+	ContextKeyEnv        ClingContextKey = "env"
+	ContextKeyInvocation ClingContextKey = "invocation"
+	ContextKeyHook       ClingContextKey = "hook"
 )
 
+type streams struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
 func contextWithCommand(ctx context.Context, command *Command) context.Context {
 	return context.WithValue(ctx, ContextKeyCommand, command)
 }
@@ -16,3 +31,52 @@ func commandFromContext(ctx context.Context) (*Command, bool) {
 	command, ok := ctx.Value(ContextKeyCommand).(*Command)
 	return command, ok
 }
+
+func contextWithPrompter(ctx context.Context, p *prompter) context.Context {
+	return context.WithValue(ctx, ContextKeyPrompter, p)
+}
+
+func prompterFromContext(ctx context.Context) (*prompter, bool) {
+	p, ok := ctx.Value(ContextKeyPrompter).(*prompter)
+	return p, ok
+}
+
+func contextWithIO(ctx context.Context, in io.Reader, out io.Writer, err io.Writer) context.Context {
+	return context.WithValue(ctx, ContextKeyIO, &streams{stdin: in, stdout: out, stderr: err})
+}
+
+func streamsFromContext(ctx context.Context) *streams {
+	if s, ok := ctx.Value(ContextKeyIO).(*streams); ok {
+		return s
+	}
+	return &streams{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr}
+}
+
+// Stdin returns the stdin of the CLI running the command.
+// It returns os.Stdin if the context is not derived from a CLIng supplied context.
+func Stdin(ctx context.Context) io.Reader {
+	return streamsFromContext(ctx).stdin
+}
+
+// Stdout returns the stdout of the CLI running the command.
+// It returns os.Stdout if the context is not derived from a CLIng supplied context.
+func Stdout(ctx context.Context) io.Writer {
+	return streamsFromContext(ctx).stdout
+}
+
+// Stderr returns the stderr of the CLI running the command.
+// It returns os.Stderr if the context is not derived from a CLIng supplied context.
+func Stderr(ctx context.Context) io.Writer {
+	return streamsFromContext(ctx).stderr
+}
+
+func contextWithEnvLookup(ctx context.Context, lookup func(key string) (string, bool)) context.Context {
+	return context.WithValue(ctx, ContextKeyEnv, lookup)
+}
+
+func envLookupFromContext(ctx context.Context) func(key string) (string, bool) {
+	if lookup, ok := ctx.Value(ContextKeyEnv).(func(key string) (string, bool)); ok {
+		return lookup
+	}
+	return os.LookupEnv
+}
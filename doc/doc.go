@@ -0,0 +1,2 @@
+// Package doc generates documentation for the commands of a cling CLI.
+package doc
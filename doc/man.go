@@ -0,0 +1,216 @@
+package doc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/binaek/cling"
+	"github.com/pkg/errors"
+)
+
+// ManHeader is the header of generated man pages.
+type ManHeader struct {
+	// Section is the section of the manual the pages belong to. Defaults to 1.
+	Section string
+	// Date is the date of the pages, like 'Jan 2006'. It is left empty by default so that the pages are reproducible.
+	Date string
+	// Source is the source of the pages. Defaults to the name and version of the CLI.
+	Source string
+	// Manual is the title of the manual.
+	Manual string
+}
+
+// GenManTree writes a man page for the CLI and one for every command path to dir,
+// named like 'cli.1' and 'cli-parent-command.1'. Hidden commands are left out.
+func GenManTree(cli *cling.CLI, header *ManHeader, dir string) error {
+	header = defaultManHeader(header, cli.UsageData())
+	return writePages(pages(cli), dir, header.Section, func(w io.Writer, p *page) {
+		writeManPage(w, p, header)
+	})
+}
+
+// NewManCommand returns a hidden 'gen-man <dir>' command that writes the man pages of the CLI to dir.
+func NewManCommand(cli *cling.CLI) *cling.Command {
+	return cling.NewCommand("gen-man", func(ctx context.Context, args []string) error {
+		var input struct {
+			Dir string `cling-name:"dir"`
+		}
+		if err := cling.Hydrate(ctx, args, &input); err != nil {
+			return err
+		}
+		return GenManTree(cli, &ManHeader{}, input.Dir)
+	}).
+		WithDescription("Generate man pages").
+		WithArgument(cling.NewStringCmdInput("dir").Required().WithDescription("The directory to write the man pages to").AsArgument()).
+		Hidden()
+}
+
+func defaultManHeader(header *ManHeader, usage *cling.UsageData) *ManHeader {
+	h := ManHeader{}
+	if header != nil {
+		h = *header
+	}
+	if h.Section == "" {
+		h.Section = "1"
+	}
+	if h.Source == "" {
+		h.Source = strings.TrimSpace(fmt.Sprintf("%s %s", usage.Name, usage.Version))
+	}
+	return &h
+}
+
+// writePages writes every page to a file in dir named after the page with the given extension.
+func writePages(pages []*page, dir string, extension string, write func(w io.Writer, p *page)) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return errors.Wrapf(err, "could not create documentation directory %s", dir)
+	}
+	buff := bytes.NewBuffer(nil)
+	for _, p := range pages {
+		buff.Reset()
+		write(buff, p)
+		path := filepath.Join(dir, fmt.Sprintf("%s.%s", p.name, extension))
+		if err := os.WriteFile(path, buff.Bytes(), 0o644); err != nil {
+			return errors.Wrapf(err, "could not write documentation page %s", path)
+		}
+	}
+	return nil
+}
+
+func writeManPage(w io.Writer, p *page, header *ManHeader) {
+	fmt.Fprintf(
+		w,
+		".TH \"%s\" \"%s\" \"%s\" \"%s\" \"%s\"\n",
+		escapeRoff(strings.ToUpper(p.name)),
+		escapeRoff(header.Section),
+		escapeRoff(header.Date),
+		escapeRoff(header.Source),
+		escapeRoff(header.Manual),
+	)
+
+	fmt.Fprintln(w, ".SH NAME")
+	if p.description == "" {
+		fmt.Fprintln(w, escapeRoff(p.name))
+	} else {
+		fmt.Fprintf(w, "%s \\- %s\n", escapeRoff(p.name), escapeRoff(p.description))
+	}
+
+	fmt.Fprintln(w, ".SH SYNOPSIS")
+	for idx, line := range p.usage {
+		if idx > 0 {
+			fmt.Fprintln(w, ".br")
+		}
+		// the command path is in bold
+		fmt.Fprintf(w, "\\fB%s\\fR%s\n", escapeRoff(p.commandPath), escapeRoff(strings.TrimPrefix(line, p.commandPath)))
+	}
+
+	if text := p.text(); text != "" {
+		fmt.Fprintln(w, ".SH DESCRIPTION")
+		fmt.Fprintln(w, escapeRoff(text))
+	}
+
+	if len(p.aliases) > 0 {
+		fmt.Fprintln(w, ".SH ALIASES")
+		fmt.Fprintln(w, escapeRoff(strings.Join(p.aliases, ", ")))
+	}
+
+	if len(p.commands) > 0 {
+		fmt.Fprintln(w, ".SH COMMANDS")
+		for _, cmd := range p.commands {
+			writeManItem(w, fmt.Sprintf("\\fB%s\\fR", escapeRoff(cmd.Name)), cmd.Description)
+		}
+	}
+
+	if len(p.args) > 0 {
+		fmt.Fprintln(w, ".SH ARGUMENTS")
+		for _, arg := range p.args {
+			tag := fmt.Sprintf("\\fB<%s>\\fR", escapeRoff(arg.Name))
+			if !arg.Required {
+				tag = fmt.Sprintf("[\\fB%s\\fR]", escapeRoff(arg.Name))
+			}
+			writeManItem(w, tag, argUsage(arg))
+			if arg.LongDescription != "" {
+				fmt.Fprintln(w, ".IP")
+				fmt.Fprintln(w, escapeRoff(arg.LongDescription))
+			}
+		}
+	}
+
+	if len(p.flagGroups) > 0 {
+		fmt.Fprintln(w, ".SH OPTIONS")
+		for _, group := range p.flagGroups {
+			if group.Title != "" {
+				fmt.Fprintf(w, ".SS %s\n", escapeRoff(group.Title))
+			}
+			for _, flag := range group.Flags {
+				tag := fmt.Sprintf("\\fB\\-\\-%s\\fR", escapeRoff(flag.Name))
+				if flag.Shorthand != "" {
+					tag = fmt.Sprintf("%s, \\fB\\-%s\\fR", tag, escapeRoff(flag.Shorthand))
+				}
+				usage := flag.Usage
+				if flag.Required {
+					usage = strings.TrimSpace(usage + " (required)")
+				}
+				writeManItem(w, tag, usage)
+			}
+		}
+	}
+
+	if len(p.envVars) > 0 {
+		fmt.Fprintln(w, ".SH ENVIRONMENT")
+		for _, env := range p.envVars {
+			writeManItem(w, fmt.Sprintf("\\fB%s\\fR", escapeRoff(env.Name)), fmt.Sprintf("Sets --%s.", env.Flag))
+		}
+	}
+
+	if len(p.examples) > 0 {
+		fmt.Fprintln(w, ".SH EXAMPLES")
+		for _, example := range p.examples {
+			if example.Description != "" {
+				fmt.Fprintln(w, ".PP")
+				fmt.Fprintln(w, escapeRoff(example.Description))
+			}
+			fmt.Fprintln(w, ".PP")
+			fmt.Fprintln(w, ".RS 4")
+			fmt.Fprintln(w, ".nf")
+			fmt.Fprintln(w, escapeRoff(example.CommandLine))
+			fmt.Fprintln(w, ".fi")
+			fmt.Fprintln(w, ".RE")
+		}
+	}
+
+	if len(p.seeAlso) > 0 {
+		refs := make([]string, 0, len(p.seeAlso))
+		for _, ref := range p.seeAlso {
+			refs = append(refs, fmt.Sprintf("\\fB%s\\fR(%s)", escapeRoff(ref.name), escapeRoff(header.Section)))
+		}
+		fmt.Fprintln(w, ".SH SEE ALSO")
+		fmt.Fprintln(w, strings.Join(refs, ", "))
+	}
+}
+
+// writeManItem writes a tagged paragraph, like a flag and its usage.
+func writeManItem(w io.Writer, tag string, body string) {
+	fmt.Fprintln(w, ".TP")
+	fmt.Fprintln(w, tag)
+	if body != "" {
+		fmt.Fprintln(w, escapeRoff(body))
+	}
+}
+
+// escapeRoff escapes the text so that roff prints it as is.
+func escapeRoff(text string) string {
+	text = strings.ReplaceAll(text, `\`, `\e`)
+	text = strings.ReplaceAll(text, "-", `\-`)
+	lines := strings.Split(text, "\n")
+	for idx, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[idx] = `\&` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,85 @@
+package doc
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/binaek/cling"
+	"github.com/binaek/cling/clingtest"
+)
+
+func newTestCLI() *cling.CLI {
+	cli := cling.NewCLI("deployer", "1.2.0").
+		WithDescription("Deploys services").
+		WithCommand(
+			cling.NewCommand("deploy", cling.NoOpHook).
+				WithDescription("Deploy a service").
+				WithLongDescription("Deploy builds the service and rolls it out.\n.Dots at the start of a line are escaped.").
+				WithAliases("d").
+				WithArgument(cling.NewStringCmdInput("service").Required().WithDescription("The service to deploy").AsArgument()).
+				WithFlag(cling.NewStringCmdInput("env").Required().WithDescription("The target environment").AsFlag()).
+				WithFlag(cling.NewIntCmdInput("replicas").WithDefault(2).WithValidator(cling.NewIntRangeValidator(1, 10)).WithDescription("The number of replicas").AsFlag().FromEnv([]string{"DEPLOYER_REPLICAS"})).
+				WithFlag(cling.NewStringCmdInput("region").WithDefault("eu-west-1").AsFlag().WithGroup("Placement")).
+				WithExample("Deploy the API to production", "deployer deploy api --env prod").
+				WithSeeAlso("config set"),
+		).
+		WithCommand(
+			cling.NewCommand("config", nil).
+				WithDescription("Manage the configuration").
+				WithChildCommand(cling.NewCommand("set", cling.NoOpHook).WithDescription("Set a configuration value")).
+				WithChildCommand(cling.NewCommand("debug", cling.NoOpHook).Hidden()),
+		)
+	return cli.WithCommand(NewManCommand(cli))
+}
+
+func TestGenManTree(t *testing.T) {
+	dir := t.TempDir()
+	if err := GenManTree(newTestCLI(), &ManHeader{Date: "Jan 2026"}, dir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	names := []string{}
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	want := []string{"deployer-config-set.1", "deployer-config.1", "deployer-deploy.1", "deployer.1"}
+	if !slices.Equal(names, want) {
+		t.Fatalf("expected pages %v, got %v", want, names)
+	}
+
+	for _, name := range []string{"deployer.1", "deployer-deploy.1"} {
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		clingtest.AssertGolden(t, filepath.Join("testdata", name+".golden"), string(content))
+	}
+}
+
+func TestManCommand(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "man")
+	res := clingtest.Run(newTestCLI(), "gen-man "+dir)
+	if res.Err != nil {
+		t.Fatalf("unexpected error: %v", res.Err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "deployer-deploy.1")); err != nil {
+		t.Fatalf("expected the man page of deploy: %v", err)
+	}
+
+	res = clingtest.Run(newTestCLI(), "--help")
+	if res.Err != nil {
+		t.Fatalf("unexpected error: %v", res.Err)
+	}
+	for _, hidden := range []string{"gen-man", "debug"} {
+		if strings.Contains(res.Stdout, hidden) {
+			t.Fatalf("expected hidden command %s to be left out of the usage, got %q", hidden, res.Stdout)
+		}
+	}
+}
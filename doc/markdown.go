@@ -0,0 +1,168 @@
+package doc
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/binaek/cling"
+)
+
+// GenMarkdownTree writes a Markdown reference page for the CLI and one for every command path to dir,
+// named like 'cli.md' and 'cli-parent-command.md', linked to the pages of their parents and children.
+// Hidden commands are left out.
+func GenMarkdownTree(cli *cling.CLI, dir string) error {
+	return writePages(pages(cli), dir, "md", writeMarkdownPage)
+}
+
+func writeMarkdownPage(w io.Writer, p *page) {
+	fmt.Fprintf(w, "# %s\n\n", p.commandPath)
+	if p.description != "" {
+		fmt.Fprintf(w, "%s\n\n", p.description)
+	}
+
+	fmt.Fprint(w, "## Usage\n\n```\n")
+	for _, line := range p.usage {
+		fmt.Fprintln(w, line)
+	}
+	fmt.Fprint(w, "```\n\n")
+
+	if text := strings.TrimSpace(p.longDescription); text != "" && text != p.description {
+		fmt.Fprintf(w, "%s\n\n", text)
+	}
+
+	if len(p.aliases) > 0 {
+		fmt.Fprintf(w, "## Aliases\n\n`%s`\n\n", strings.Join(p.aliases, "`, `"))
+	}
+
+	if len(p.commands) > 0 {
+		rows := [][]string{}
+		for _, cmd := range p.commands {
+			name := markdownCode(cmd.Name)
+			if child, ok := p.child(cmd.Name); ok {
+				name = markdownLink(child, name)
+			}
+			rows = append(rows, []string{name, cmd.Description})
+		}
+		fmt.Fprint(w, "## Commands\n\n")
+		writeMarkdownTable(w, []string{"Command", "Description"}, rows)
+	}
+
+	if len(p.args) > 0 {
+		rows := [][]string{}
+		for _, arg := range p.args {
+			rows = append(rows, []string{
+				markdownCode(arg.Name),
+				strings.TrimSpace(arg.Description + " " + arg.LongDescription),
+				arg.Constraint,
+				yesOrEmpty(arg.Required),
+				markdownDefault(arg.HasDefault, arg.Default),
+			})
+		}
+		fmt.Fprint(w, "## Arguments\n\n")
+		writeMarkdownTable(w, []string{"Argument", "Description", "Constraint", "Required", "Default"}, rows)
+	}
+
+	if len(p.flagGroups) > 0 {
+		fmt.Fprint(w, "## Flags\n\n")
+		for _, group := range p.flagGroups {
+			if group.Title != "" {
+				fmt.Fprintf(w, "### %s\n\n", group.Title)
+			}
+			rows := [][]string{}
+			for _, flag := range group.Flags {
+				name := markdownCode("--" + flag.Name)
+				if flag.Shorthand != "" {
+					name = fmt.Sprintf("%s, %s", name, markdownCode("-"+flag.Shorthand))
+				}
+				envVars := make([]string, 0, len(flag.EnvVars))
+				for _, env := range flag.EnvVars {
+					envVars = append(envVars, markdownCode(env))
+				}
+				rows = append(rows, []string{
+					name,
+					flag.Description,
+					flag.Constraint,
+					yesOrEmpty(flag.Required),
+					markdownDefault(flag.HasDefault, flag.Default),
+					strings.Join(envVars, ", "),
+				})
+			}
+			writeMarkdownTable(w, []string{"Flag", "Description", "Constraint", "Required", "Default", "Environment"}, rows)
+		}
+	}
+
+	if len(p.envVars) > 0 {
+		rows := [][]string{}
+		for _, env := range p.envVars {
+			rows = append(rows, []string{markdownCode(env.Name), markdownCode("--" + env.Flag)})
+		}
+		fmt.Fprint(w, "## Environment Variables\n\n")
+		writeMarkdownTable(w, []string{"Variable", "Flag"}, rows)
+	}
+
+	if len(p.examples) > 0 {
+		fmt.Fprint(w, "## Examples\n\n")
+		for _, example := range p.examples {
+			if example.Description != "" {
+				fmt.Fprintf(w, "%s\n\n", example.Description)
+			}
+			fmt.Fprintf(w, "```sh\n%s\n```\n\n", example.CommandLine)
+		}
+	}
+
+	if len(p.seeAlso) > 0 {
+		fmt.Fprint(w, "## See Also\n\n")
+		for _, ref := range p.seeAlso {
+			if ref.description == "" {
+				fmt.Fprintf(w, "* %s\n", markdownLink(ref, ref.commandPath))
+				continue
+			}
+			fmt.Fprintf(w, "* %s - %s\n", markdownLink(ref, ref.commandPath), ref.description)
+		}
+	}
+}
+
+func writeMarkdownTable(w io.Writer, header []string, rows [][]string) {
+	fmt.Fprintf(w, "| %s |\n", strings.Join(header, " | "))
+	fmt.Fprintf(w, "|%s\n", strings.Repeat(" --- |", len(header)))
+	for _, row := range rows {
+		cells := make([]string, 0, len(row))
+		for _, cell := range row {
+			cells = append(cells, escapeMarkdownCell(cell))
+		}
+		fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | "))
+	}
+	fmt.Fprintln(w)
+}
+
+func markdownLink(p *page, text string) string {
+	return fmt.Sprintf("[%s](%s.md)", text, p.name)
+}
+
+func markdownCode(text string) string {
+	return "`" + text + "`"
+}
+
+func markdownDefault(hasDefault bool, value string) string {
+	if !hasDefault {
+		return ""
+	}
+	if value == "" {
+		return `""`
+	}
+	return markdownCode(value)
+}
+
+// escapeMarkdownCell keeps the text in a single table cell.
+func escapeMarkdownCell(text string) string {
+	text = strings.ReplaceAll(text, "|", `\|`)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func yesOrEmpty(value bool) string {
+	if value {
+		return "yes"
+	}
+	return ""
+}
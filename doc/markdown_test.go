@@ -0,0 +1,39 @@
+package doc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/binaek/cling/clingtest"
+)
+
+func TestGenReferenceTree(t *testing.T) {
+	tests := []struct {
+		extension string
+		gen       func(dir string) error
+	}{
+		{"md", func(dir string) error { return GenMarkdownTree(newTestCLI(), dir) }},
+		{"rst", func(dir string) error { return GenReSTTree(newTestCLI(), dir) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.extension, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := tt.gen(dir); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, name := range []string{"deployer", "deployer-deploy", "deployer-config"} {
+				file := name + "." + tt.extension
+				content, err := os.ReadFile(filepath.Join(dir, file))
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				clingtest.AssertGolden(t, filepath.Join("testdata", file+".golden"), string(content))
+			}
+			if _, err := os.Stat(filepath.Join(dir, "deployer-config-debug."+tt.extension)); !os.IsNotExist(err) {
+				t.Fatalf("expected no page for the hidden command, got: %v", err)
+			}
+		})
+	}
+}
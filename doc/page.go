@@ -0,0 +1,133 @@
+package doc
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/binaek/cling"
+)
+
+// page is the documentation of the CLI or one of its commands, from the data the help is rendered with.
+type page struct {
+	// name is the name of the page, like 'cli-parent-command'.
+	name string
+	// commandPath is the name of the CLI followed by the path of the command, like 'cli parent command'.
+	commandPath     string
+	description     string
+	longDescription string
+	usage           []string
+	aliases         []string
+	// commands lists the direct children of the command, or the top level commands of the CLI.
+	commands   []cling.CommandData
+	args       []cling.ArgData
+	flagGroups []cling.FlagGroupData
+	envVars    []cling.EnvVarData
+	examples   []cling.ExampleData
+	// seeAlso lists the pages of the parent, the children and the related commands, without duplicates.
+	seeAlso []*page
+}
+
+// pages returns the page of the CLI followed by the pages of its commands, depth first.
+// Hidden commands are left out.
+func pages(cli *cling.CLI) []*page {
+	usage := cli.UsageData()
+	root := &page{
+		name:            usage.Name,
+		commandPath:     usage.Name,
+		description:     usage.Description,
+		longDescription: usage.LongDescription,
+		usage:           usage.Usage,
+		commands:        topLevelCommands(usage.Commands),
+		args:            usage.Args,
+		flagGroups:      usage.FlagGroups,
+		envVars:         usage.EnvVars,
+	}
+	all := []*page{root}
+	seeAlso := map[*page][]string{}
+	for _, cmd := range root.commands {
+		seeAlso[root] = append(seeAlso[root], pageName(usage.Name, []string{cmd.Name}))
+	}
+
+	for _, command := range cli.HelpData() {
+		p := &page{
+			name:            pageName(command.CLIName, command.Path),
+			commandPath:     command.CommandPath,
+			description:     command.Description,
+			longDescription: command.LongDescription,
+			usage:           []string{command.Usage},
+			aliases:         command.Aliases,
+			commands:        topLevelCommands(command.Commands),
+			args:            command.Args,
+			flagGroups:      command.FlagGroups,
+			envVars:         command.EnvVars,
+			examples:        command.Examples,
+		}
+		seeAlso[p] = append(seeAlso[p], pageName(command.CLIName, command.Path[:len(command.Path)-1]))
+		for _, cmd := range p.commands {
+			seeAlso[p] = append(seeAlso[p], pageName(command.CLIName, append(slices.Clone(command.Path), cmd.Name)))
+		}
+		for _, related := range command.SeeAlso {
+			seeAlso[p] = append(seeAlso[p], strings.Join(strings.Fields(related), "-"))
+		}
+		all = append(all, p)
+	}
+
+	// references only resolve to commands that have a page, like the built-in help command does not
+	byName := map[string]*page{}
+	for _, p := range all {
+		byName[p.name] = p
+	}
+	for _, p := range all {
+		for _, name := range seeAlso[p] {
+			if ref, ok := byName[name]; ok && !slices.Contains(p.seeAlso, ref) {
+				p.seeAlso = append(p.seeAlso, ref)
+			}
+		}
+	}
+	return all
+}
+
+// pageName returns the name of the page of the command path, like 'cli-parent-command'.
+func pageName(cliName string, path []string) string {
+	return strings.Join(append([]string{cliName}, path...), "-")
+}
+
+// topLevelCommands returns the commands at the top of a listing, leaving out their children.
+func topLevelCommands(commands []cling.CommandData) []cling.CommandData {
+	return slices.DeleteFunc(slices.Clone(commands), func(cmd cling.CommandData) bool {
+		return cmd.Depth > 0
+	})
+}
+
+// text returns the long description of the page, or its description if it has none.
+func (p *page) text() string {
+	if text := strings.TrimSpace(p.longDescription); text != "" {
+		return text
+	}
+	return strings.TrimSpace(p.description)
+}
+
+// child returns the page of the direct child with the given name, if it has one.
+func (p *page) child(name string) (*page, bool) {
+	for _, ref := range p.seeAlso {
+		if ref.name == pageName(p.name, []string{name}) {
+			return ref, true
+		}
+	}
+	return nil, false
+}
+
+// argUsage returns the description of the argument followed by its constraint and default value.
+func argUsage(arg cling.ArgData) string {
+	parts := []string{}
+	if arg.Description != "" {
+		parts = append(parts, arg.Description)
+	}
+	if arg.Constraint != "" {
+		parts = append(parts, "("+arg.Constraint+")")
+	}
+	if arg.HasDefault {
+		parts = append(parts, "(default: "+arg.Default+")")
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,170 @@
+package doc
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/binaek/cling"
+)
+
+// GenReSTTree writes a reStructuredText reference page for the CLI and one for every command path to dir,
+// named like 'cli.rst' and 'cli-parent-command.rst'. Every page starts with a label named after the page,
+// which the pages of its parent and children link to with the Sphinx :ref: role.
+// Hidden commands are left out.
+func GenReSTTree(cli *cling.CLI, dir string) error {
+	return writePages(pages(cli), dir, "rst", writeReSTPage)
+}
+
+func writeReSTPage(w io.Writer, p *page) {
+	fmt.Fprintf(w, ".. _%s:\n\n", p.name)
+	writeReSTHeading(w, p.commandPath, "=")
+	if p.description != "" {
+		fmt.Fprintf(w, "%s\n\n", p.description)
+	}
+
+	writeReSTHeading(w, "Usage", "-")
+	fmt.Fprint(w, "::\n\n")
+	for _, line := range p.usage {
+		fmt.Fprintf(w, "  %s\n", line)
+	}
+	fmt.Fprintln(w)
+
+	if text := strings.TrimSpace(p.longDescription); text != "" && text != p.description {
+		fmt.Fprintf(w, "%s\n\n", text)
+	}
+
+	if len(p.aliases) > 0 {
+		writeReSTHeading(w, "Aliases", "-")
+		fmt.Fprintf(w, "``%s``\n\n", strings.Join(p.aliases, "``, ``"))
+	}
+
+	if len(p.commands) > 0 {
+		rows := [][]string{}
+		for _, cmd := range p.commands {
+			name := reSTCode(cmd.Name)
+			if child, ok := p.child(cmd.Name); ok {
+				name = reSTLink(child, cmd.Name)
+			}
+			rows = append(rows, []string{name, cmd.Description})
+		}
+		writeReSTHeading(w, "Commands", "-")
+		writeReSTTable(w, []string{"Command", "Description"}, rows)
+	}
+
+	if len(p.args) > 0 {
+		rows := [][]string{}
+		for _, arg := range p.args {
+			rows = append(rows, []string{
+				reSTCode(arg.Name),
+				strings.TrimSpace(arg.Description + " " + arg.LongDescription),
+				arg.Constraint,
+				yesOrEmpty(arg.Required),
+				reSTDefault(arg.HasDefault, arg.Default),
+			})
+		}
+		writeReSTHeading(w, "Arguments", "-")
+		writeReSTTable(w, []string{"Argument", "Description", "Constraint", "Required", "Default"}, rows)
+	}
+
+	if len(p.flagGroups) > 0 {
+		writeReSTHeading(w, "Flags", "-")
+		for _, group := range p.flagGroups {
+			if group.Title != "" {
+				writeReSTHeading(w, group.Title, "~")
+			}
+			rows := [][]string{}
+			for _, flag := range group.Flags {
+				name := reSTCode("--" + flag.Name)
+				if flag.Shorthand != "" {
+					name = fmt.Sprintf("%s, %s", name, reSTCode("-"+flag.Shorthand))
+				}
+				envVars := make([]string, 0, len(flag.EnvVars))
+				for _, env := range flag.EnvVars {
+					envVars = append(envVars, reSTCode(env))
+				}
+				rows = append(rows, []string{
+					name,
+					flag.Description,
+					flag.Constraint,
+					yesOrEmpty(flag.Required),
+					reSTDefault(flag.HasDefault, flag.Default),
+					strings.Join(envVars, ", "),
+				})
+			}
+			writeReSTTable(w, []string{"Flag", "Description", "Constraint", "Required", "Default", "Environment"}, rows)
+		}
+	}
+
+	if len(p.envVars) > 0 {
+		rows := [][]string{}
+		for _, env := range p.envVars {
+			rows = append(rows, []string{reSTCode(env.Name), reSTCode("--" + env.Flag)})
+		}
+		writeReSTHeading(w, "Environment Variables", "-")
+		writeReSTTable(w, []string{"Variable", "Flag"}, rows)
+	}
+
+	if len(p.examples) > 0 {
+		writeReSTHeading(w, "Examples", "-")
+		for _, example := range p.examples {
+			if example.Description != "" {
+				fmt.Fprintf(w, "%s\n\n", example.Description)
+			}
+			fmt.Fprintf(w, ".. code-block:: sh\n\n  %s\n\n", example.CommandLine)
+		}
+	}
+
+	if len(p.seeAlso) > 0 {
+		writeReSTHeading(w, "See Also", "-")
+		for _, ref := range p.seeAlso {
+			if ref.description == "" {
+				fmt.Fprintf(w, "* %s\n", reSTLink(ref, ref.commandPath))
+				continue
+			}
+			fmt.Fprintf(w, "* %s - %s\n", reSTLink(ref, ref.commandPath), ref.description)
+		}
+	}
+}
+
+func writeReSTHeading(w io.Writer, title string, underline string) {
+	fmt.Fprintf(w, "%s\n%s\n\n", title, strings.Repeat(underline, len(title)))
+}
+
+// writeReSTTable writes the rows as a list-table, which does not need its columns to be aligned.
+func writeReSTTable(w io.Writer, header []string, rows [][]string) {
+	fmt.Fprint(w, ".. list-table::\n   :header-rows: 1\n\n")
+	for _, row := range append([][]string{header}, rows...) {
+		for idx, cell := range row {
+			prefix := "     -"
+			if idx == 0 {
+				prefix = "   * -"
+			}
+			cell = strings.Join(strings.Fields(cell), " ")
+			if cell == "" {
+				fmt.Fprintln(w, prefix)
+				continue
+			}
+			fmt.Fprintf(w, "%s %s\n", prefix, cell)
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+func reSTLink(p *page, text string) string {
+	return fmt.Sprintf(":ref:`%s <%s>`", text, p.name)
+}
+
+func reSTCode(text string) string {
+	return "``" + text + "``"
+}
+
+func reSTDefault(hasDefault bool, value string) string {
+	if !hasDefault {
+		return ""
+	}
+	if value == "" {
+		return `""`
+	}
+	return reSTCode(value)
+}
@@ -0,0 +1,136 @@
+package cling
+
+import (
+	"context"
+	stdErrs "errors"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var ErrInvalidExample = errors.New("invalid example")
+
+type example struct {
+	description string
+	commandLine string
+}
+
+// ValidateExamples checks that the examples of every command parse against the command:
+// they must start with the name of the CLI, resolve to the command they belong to,
+// use only declared flags, pass all required flags and the right number of arguments,
+// and give values that parse and pass the validators of their inputs.
+// It also checks that every see-also reference names an existing command.
+//
+// It is meant to be called from tests.
+func (c *CLI) ValidateExamples() error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+	errs := []error{}
+	_ = c.Walk(func(_ []*Command, cmd *Command) error {
+		for _, ex := range cmd.examples {
+			if err := c.validateExample(cmd, ex.commandLine); err != nil {
+				errs = append(errs, errors.Wrapf(err, "example '%s' of '%s'", ex.commandLine, strings.Join(cmd.commandPath(), " ")))
+			}
+		}
+		for _, path := range cmd.seeAlso {
+			names := strings.Fields(path)
+			if found := c.findCommand(names); found == nil || len(found.pathToRoot()) != len(names) {
+				errs = append(errs, errors.Wrapf(ErrInvalidExample, "see also '%s' of '%s' is not a command", path, strings.Join(cmd.commandPath(), " ")))
+			}
+		}
+		return nil
+	})
+	return stdErrs.Join(errs...)
+}
+
+func (c *CLI) validateExample(cmd *Command, commandLine string) error {
+	args, err := SplitCommandLine(commandLine)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 || args[0] != c.name {
+		return errors.Wrapf(ErrInvalidExample, "must start with '%s'", c.name)
+	}
+
+	flags, positionals := parseArguments(args[1:])
+	// transformers read the environment of the CLI
+	ctx := contextWithEnvLookup(context.Background(), c.lookupEnv)
+
+	resolved, positionals, err := c.resolveCommand(positionals)
+	if err != nil {
+		return err
+	}
+	if resolved != cmd {
+		return errors.Wrap(ErrInvalidExample, "does not run the command it belongs to")
+	}
+
+	for name := range flags {
+		if !slices.ContainsFunc(cmd.flags, func(flag CmdFlag) bool { return flag.Name() == name }) && !isGlobalFlag(name) {
+			return errors.Wrapf(ErrInvalidExample, "unknown flag '--%s'", name)
+		}
+	}
+	for _, flag := range cmd.flags {
+		values, ok := flags[flag.Name()]
+		if !ok {
+			if flag.IsRequired() {
+				return errors.Wrapf(ErrInvalidExample, "missing required flag '--%s'", flag.Name())
+			}
+			continue
+		}
+		if flag.IsRequired() && flag.ValueType().Kind() != reflect.Bool && !slices.ContainsFunc(values, func(v string) bool { return v != "" }) {
+			return errors.Wrapf(ErrInvalidExample, "missing value for required flag '--%s'", flag.Name())
+		}
+		if err := validateExampleValue(ctx, flag, values); err != nil {
+			return errors.Wrapf(ErrInvalidExample, "invalid value for flag '--%s': %v", flag.Name(), err)
+		}
+	}
+
+	required := 0
+	for _, arg := range cmd.arguments {
+		if arg.IsRequired() {
+			required++
+		}
+	}
+	if len(positionals) < required {
+		return errors.Wrapf(ErrInvalidExample, "needs at least %d arguments, got %d", required, len(positionals))
+	}
+	if len(positionals) > len(cmd.arguments) && !cmd.hasTrailingSliceArgument() {
+		return errors.Wrapf(ErrInvalidExample, "takes at most %d arguments, got %d", len(cmd.arguments), len(positionals))
+	}
+	for idx, arg := range cmd.arguments {
+		if idx >= len(positionals) {
+			break
+		}
+		values := positionals[idx : idx+1]
+		if idx == len(cmd.arguments)-1 && cmd.hasTrailingSliceArgument() {
+			values = positionals[idx:]
+		}
+		if err := validateExampleValue(ctx, arg, values); err != nil {
+			return errors.Wrapf(ErrInvalidExample, "invalid value for argument '%s': %v", arg.Name(), err)
+		}
+	}
+	return nil
+}
+
+// validateExampleValue runs the values through the parsing, transformation and validation
+// the input goes through when the command runs, into a scratch value.
+func validateExampleValue(ctx context.Context, input CmdInput, values []string) error {
+	return pipelineFor(input).setField(ctx, reflect.New(input.ValueType()).Elem(), values)
+}
+
+// isGlobalFlag reports whether the flag is handled by the CLI rather than by commands.
+// The confirmation flag is only valid for the commands that declare it.
+func isGlobalFlag(name string) bool {
+	return name != confirmationFlag && isBuiltinSwitch(name)
+}
+
+// hasTrailingSliceArgument reports whether the last argument of the command takes all remaining positionals.
+func (c *Command) hasTrailingSliceArgument() bool {
+	if len(c.arguments) == 0 {
+		return false
+	}
+	return c.arguments[len(c.arguments)-1].ValueType().Kind() == reflect.Slice
+}
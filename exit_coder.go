@@ -1,6 +1,10 @@
 package cling
 
-import "os"
+import (
+	"errors"
+	"io"
+	"os"
+)
 
 type ExitCoder interface {
 	ExitCode() int
@@ -31,32 +35,38 @@ func NewExitCoder(err error, code int) ExitCoder {
 }
 
 // ExitWithErrorMessage exits the program with a non-zero exit code if the given error is non-nil.
-// If the given error is an `ExitCoder`, the exit code will be taken from the error, otherwise it will be 1.
+// If the given error is or wraps an `ExitCoder`, the exit code will be taken from the error, otherwise it will be 1.
 // The error message will be printed to stderr as "Error: <message>\n".
 //
 // Uses `os.Exit` to exit the program. This function should be used only after when all cleanups are done.
 func ExitWithMessage(err error) {
-	exitWithError(err, true)
+	exitWithError(os.Stderr, err, true)
 }
 
 // ExitWithErrorCode - exits the program with a non-zero exit code if the given error is non-nil.
-// If the given error is an `ExitCoder`, the exit code will be taken from the error, otherwise it will be 1.
+// If the given error is or wraps an `ExitCoder`, the exit code will be taken from the error, otherwise it will be 1.
 //
 // Uses `os.Exit` to exit the program. This function should be used only after when all cleanups are done.
 func Exit(err error) {
-	exitWithError(err, false)
+	exitWithError(os.Stderr, err, false)
 }
 
-func exitWithError(err error, printMessage bool) {
+func exitWithError(stderr io.Writer, err error, printMessage bool) {
 	if err == nil {
 		return
 	}
 	if printMessage {
-		_, _ = os.Stderr.WriteString("Error: " + err.Error() + "\n")
+		_, _ = io.WriteString(stderr, "Error: "+err.Error()+"\n")
 	}
-	exitCode := 1
-	if exitErr, ok := err.(ExitCoder); ok {
-		exitCode = exitErr.ExitCode()
+	os.Exit(exitCode(err))
+}
+
+// exitCode returns the exit code of the first ExitCoder in the tree of the error, or 1 if there is none.
+// Errors of post-run hooks are joined with the error of the action, so the ExitCoder may be wrapped.
+func exitCode(err error) int {
+	var exitErr ExitCoder
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
 	}
-	os.Exit(exitCode)
+	return 1
 }
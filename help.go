@@ -1,100 +1,281 @@
 package cling
 
 import (
-	"bytes"
 	"fmt"
 	"slices"
 	"strings"
-
-	"github.com/olekukonko/tablewriter"
 )
 
-func (c *CLI) printUsage() {
-	fmt.Fprintf(c.stdout, "Usage: %s [command] [flags] [arguments]\n\n", c.name)
-	fmt.Fprintln(c.stdout, "Available Commands:")
-	for _, cmd := range c.commands {
-		fmt.Fprintf(c.stdout, "  %s\t%s\n", cmd.name, cmd.description)
-		for _, child := range cmd.children { // Print subcommands
-			fmt.Fprintf(c.stdout, "    %s\t%s\n", child.name, child.description)
+func (c *CLI) printUsage(format helpFormat) error {
+	tmpl, err := parseTemplate("usage", c.usageTemplate, format)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(c.stdout, c.UsageData())
+}
+
+func (c *Command) printHelp(cli *CLI, format helpFormat) error {
+	tmpl, err := parseTemplate("help", cli.helpTemplate, format)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(cli.stdout, c.helpData(cli))
+}
+
+// UsageData returns the data the usage template is executed with.
+func (c *CLI) UsageData() *UsageData {
+	data := &UsageData{
+		Name:            c.name,
+		Version:         c.version,
+		Description:     c.description,
+		LongDescription: c.longDescription,
+		Usage:           []string{},
+		HelpTopics:      []HelpTopicData{},
+	}
+
+	if c.root.action != nil {
+		data.Usage = append(data.Usage, c.root.usageLine(c.name))
+		data.Args = argsData(c.root.arguments)
+		data.EnvVars = envVarsData(c.root.flags)
+	}
+	data.Usage = append(data.Usage, fmt.Sprintf("%s [command] [flags] [arguments]", c.name))
+
+	// built-in commands are listed with the ungrouped commands, unless a command with the same name is declared
+	builtins := []CommandData{}
+	if c.findCommand([]string{helpCommand}) == nil {
+		builtins = append(builtins, CommandData{Name: helpCommand, Path: helpCommand, Description: "Help about any command or topic"})
+	}
+	if c.treeCommand && c.findCommand([]string{treeCommand}) == nil {
+		builtins = append(builtins, CommandData{Name: treeCommand, Path: treeCommand, Description: "Show the command tree"})
+	}
+	data.CommandGroups = c.commandGroupsData(c.commands, c.commandDepth)
+	if len(builtins) > 0 {
+		if len(data.CommandGroups) == 0 || data.CommandGroups[0].Title != "" {
+			data.CommandGroups = append([]CommandGroupData{{Commands: []CommandData{}}}, data.CommandGroups...)
 		}
+		data.CommandGroups[0].Commands = append(data.CommandGroups[0].Commands, builtins...)
 	}
+	data.Commands = flattenCommandGroups(data.CommandGroups)
 
-	fmt.Fprintln(c.stdout, "\nFlags:")
-	fmt.Fprintln(c.stdout, "  --help\tShow help information")
-	fmt.Fprintln(c.stdout, "  --version\tShow version information")
+	for _, topic := range c.helpTopics {
+		data.HelpTopics = append(data.HelpTopics, HelpTopicData{
+			Name:    topic.name,
+			Summary: topic.summary(),
+			Body:    topic.body,
+		})
+	}
+
+	globalFlags := []FlagData{}
+	for _, s := range builtinSwitches {
+		if s.description == "" || (s.name == "no-input" && !c.interactive) {
+			// --no-input only matters when the CLI prompts
+			continue
+		}
+		globalFlags = append(globalFlags, FlagData{Name: s.name, Shorthand: s.shorthand, Description: s.description, Usage: s.description})
+	}
+	if c.root.action != nil {
+		for _, flag := range c.root.flags {
+			globalFlags = append(globalFlags, flagData(flag))
+		}
+	}
+	data.FlagGroups = c.flagGroupsData(globalFlags)
 
-	fmt.Fprintf(c.stdout, "\nUse \"%s [command] --help\" for more information about a command.\n", c.name)
+	return data
 }
 
-func (c *Command) printHelp(cli *CLI) error {
-	path2Root := c.pathToRoot()
-	slices.Reverse(path2Root)
-	pathStr := make([]string, len(path2Root))
-	for i, parent := range path2Root {
-		pathStr[i] = parent.name
+// HelpData returns the data the help template is executed with for every command of the CLI, depth first.
+// Hidden commands and their children are left out.
+func (c *CLI) HelpData() []*HelpData {
+	data := []*HelpData{}
+	var collect func(commands []*Command)
+	collect = func(commands []*Command) {
+		for _, cmd := range visibleCommands(commands) {
+			data = append(data, cmd.helpData(c))
+			collect(cmd.children)
+		}
 	}
+	collect(c.commands)
+	return data
+}
 
-	fmt.Fprintln(cli.stdout, c.longDescription)
+// helpData returns the data the help template is executed with.
+func (c *Command) helpData(cli *CLI) *HelpData {
+	path := c.commandPath()
+	data := &HelpData{
+		CLIName:         cli.name,
+		Name:            c.name,
+		Path:            path,
+		CommandPath:     strings.Join(append([]string{cli.name}, path...), " "),
+		Aliases:         c.aliases,
+		Description:     c.description,
+		LongDescription: c.longDescription,
+		Usage:           c.usageLine(cli.name),
+		CommandGroups:   cli.commandGroupsData(c.children, cli.commandDepth),
+		Examples:        []ExampleData{},
+		SeeAlso:         []string{},
+		Args:            argsData(c.arguments),
+		FlagGroups:      []FlagGroupData{},
+		EnvVars:         envVarsData(c.flags),
+	}
+	for _, ex := range c.examples {
+		data.Examples = append(data.Examples, ExampleData{Description: ex.description, CommandLine: ex.commandLine})
+	}
+	for _, path := range c.seeAlso {
+		data.SeeAlso = append(data.SeeAlso, fmt.Sprintf("%s %s", cli.name, path))
+	}
+	if len(c.flags) > 0 {
+		flags := make([]FlagData, 0, len(c.flags))
+		for _, flag := range c.flags {
+			flags = append(flags, flagData(flag))
+		}
+		data.FlagGroups = cli.flagGroupsData(flags)
+	}
+	data.Commands = flattenCommandGroups(data.CommandGroups)
+	return data
+}
 
-	fmt.Fprintln(cli.stdout, "Usage: ")
-	usageString := fmt.Sprintf("%s %s", cli.name, strings.Join(pathStr, " "))
-	if len(c.children) > 0 {
-		usageString = fmt.Sprintf("%s <command>", usageString)
+// commandsData lists the commands and their children depth first, down to maxDepth levels.
+// A maxDepth of 0 lists the full tree.
+func commandsData(commands []*Command, depth int, maxDepth int, order HelpOrder) []CommandData {
+	data := []CommandData{}
+	if maxDepth > 0 && depth >= maxDepth {
+		return data
+	}
+	if order == HelpOrderAlphabetical {
+		commands = slices.Clone(commands)
+		slices.SortStableFunc(commands, func(a, b *Command) int {
+			return strings.Compare(a.name, b.name)
+		})
+	}
+	for _, cmd := range visibleCommands(commands) {
+		path := cmd.commandPath()
+		data = append(data, CommandData{
+			Name:        cmd.name,
+			Path:        strings.Join(path[len(path)-depth-1:], " "),
+			Aliases:     cmd.aliases,
+			Description: cmd.description,
+			Group:       cmd.group,
+			Depth:       depth,
+		})
+		data = append(data, commandsData(cmd.children, depth+1, maxDepth, order)...)
+	}
+	return data
+}
+
+func flagData(flag CmdFlag) FlagData {
+	data := FlagData{
+		Name:        flag.Name(),
+		Description: flag.Description(),
+		Group:       flag.Group(),
+		Constraint:  inputConstraint(flag),
+		Required:    flag.IsRequired(),
+		HasDefault:  flag.HasDefault(),
+		EnvVars:     flag.EnvSources(),
+	}
+	if data.HasDefault {
+		data.Default = strings.Join(defaultValueStrings(flag), ",")
 	}
 
-	if len(c.arguments) > 0 {
-		for _, arg := range c.arguments {
-			if !arg.isRequired() {
-				usageString = fmt.Sprintf("%s [%s]", usageString, arg.Name())
-				continue
-			}
-			usageString = fmt.Sprintf("%s <%s>", usageString, arg.Name())
+	data.Usage = inputUsage(data.Description, data.Constraint, data.HasDefault, data.Default, data.EnvVars)
+	return data
+}
+
+func argsData(args []CmdArg) []ArgData {
+	data := make([]ArgData, 0, len(args))
+	for _, arg := range args {
+		argData := ArgData{
+			Name:            arg.Name(),
+			Description:     arg.Description(),
+			LongDescription: arg.LongDescription(),
+			Constraint:      inputConstraint(arg),
+			Required:        arg.IsRequired(),
+			HasDefault:      arg.HasDefault(),
 		}
+		if argData.HasDefault {
+			argData.Default = strings.Join(defaultValueStrings(arg), ",")
+		}
+		argData.Usage = inputUsage(argData.Description, argData.Constraint, argData.HasDefault, argData.Default, nil)
+		data = append(data, argData)
 	}
+	return data
+}
 
-	if len(c.flags) > 0 {
-		usageString = fmt.Sprintf("%s [flags]", usageString)
+// inputUsage joins the description of an input with its constraint, its default value and its environment sources.
+func inputUsage(description string, constraint string, hasDefault bool, def string, envVars []string) string {
+	parts := []string{}
+	if description != "" {
+		parts = append(parts, description)
+	}
+	if constraint != "" {
+		parts = append(parts, fmt.Sprintf("(%s)", constraint))
+	}
+	if hasDefault {
+		parts = append(parts, fmt.Sprintf("(default: %s)", def))
 	}
-	fmt.Fprintf(cli.stdout, "  %s\n", usageString)
+	if len(envVars) > 0 {
+		parts = append(parts, fmt.Sprintf("(env: %s)", strings.Join(envVars, ", ")))
+	}
+	return strings.Join(parts, " ")
+}
 
-	// Print available commands if any
-	if len(c.children) > 0 {
-		buff := bytes.NewBuffer(nil)
-		buff.WriteString("Available Commands:\n")
-		for _, child := range c.children {
-			buff.WriteString(
-				fmt.Sprintf("  %s\t%s\n", child.name, child.description),
-			)
+func envVarsData(flags []CmdFlag) []EnvVarData {
+	data := []EnvVarData{}
+	for _, flag := range flags {
+		for _, env := range flag.EnvSources() {
+			data = append(data, EnvVarData{Name: env, Flag: flag.Name()})
 		}
-		fmt.Fprintln(cli.stdout)
-		fmt.Fprintln(cli.stdout, buff.String())
 	}
+	return data
+}
 
-	// Print flags
-	if len(c.flags) > 0 {
-		buff := bytes.NewBuffer(nil)
-		buff.WriteString("Flags:\n")
-		flagsTable := tablewriter.NewWriter(buff)
-		flagsTable.SetBorder(false)
-		flagsTable.SetColumnSeparator("")
-		for _, flag := range c.flags {
-			flagsTable.Append(
-				[]string{
-					fmt.Sprintf("--%s", flag.Name()),
-					flag.Description(),
-				},
-			)
+// inputConstraint describes the values the validators of the input accept.
+func inputConstraint(input CmdInput) string {
+	constraints := []string{}
+	if provider, ok := input.(ValidatorProvider); ok {
+		if constraint := describeValidator(provider.getValidator()); constraint != "" {
+			constraints = append(constraints, constraint)
 		}
-		flagsTable.Render()
+	}
+	if provider, ok := input.(elementValidatorProvider); ok {
+		if constraint := describeValidator(provider.getElementValidator()); constraint != "" {
+			constraints = append(constraints, fmt.Sprintf("each %s", constraint))
+		}
+	}
+	return strings.Join(constraints, ", ")
+}
 
-		fmt.Fprintln(cli.stdout)
-		fmt.Fprintln(cli.stdout, buff.String())
-		fmt.Fprintln(cli.stdout)
+// commandPath returns the names of the commands from the top level command down to this one.
+func (c *Command) commandPath() []string {
+	path2Root := c.pathToRoot()
+	slices.Reverse(path2Root)
+	pathStr := make([]string, 0, len(path2Root))
+	for _, parent := range path2Root {
+		if parent.name == "" {
+			// the root command of the CLI has no name
+			continue
+		}
+		pathStr = append(pathStr, parent.name)
 	}
+	return pathStr
+}
 
+// usageLine returns the usage of the command, like 'cli cmd <command> <arg> [flags]'.
+func (c *Command) usageLine(cliName string) string {
+	usageString := strings.Join(append([]string{cliName}, c.commandPath()...), " ")
 	if len(c.children) > 0 {
-		fmt.Fprintf(cli.stdout, "Use \"%s %s [command] --help\" for more information about a command.\n", cli.name, strings.Join(pathStr, " "))
+		usageString = fmt.Sprintf("%s <command>", usageString)
+	}
+
+	for _, arg := range c.arguments {
+		if !arg.IsRequired() {
+			usageString = fmt.Sprintf("%s [%s]", usageString, arg.Name())
+			continue
+		}
+		usageString = fmt.Sprintf("%s <%s>", usageString, arg.Name())
 	}
 
-	return nil
+	if len(c.flags) > 0 {
+		usageString = fmt.Sprintf("%s [flags]", usageString)
+	}
+	return usageString
 }
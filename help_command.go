@@ -0,0 +1,41 @@
+package cling
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const helpCommand = "help"
+
+type helpTopic struct {
+	name string
+	body string
+}
+
+// summary returns the first line of the body of the topic.
+func (t *helpTopic) summary() string {
+	summary, _, _ := strings.Cut(strings.TrimSpace(t.body), "\n")
+	return summary
+}
+
+// runHelp runs the built-in help command for the given command path or topic name.
+func (c *CLI) runHelp(names []string, format helpFormat) error {
+	if len(names) == 0 {
+		return c.printUsage(format)
+	}
+
+	for _, topic := range c.helpTopics {
+		if topic.name == strings.Join(names, " ") {
+			fmt.Fprintln(c.stdout, strings.TrimSpace(topic.body))
+			return nil
+		}
+	}
+
+	command := c.findCommand(names)
+	if command == nil || len(command.pathToRoot()) != len(names) {
+		return errors.Wrapf(ErrUnknownCommand, "no help for '%s'", strings.Join(names, " "))
+	}
+	return command.printHelp(c, format)
+}
@@ -0,0 +1,114 @@
+package cling
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+const (
+	// defaultHelpWidth is the width help is wrapped at when the width of the terminal is unknown.
+	defaultHelpWidth = 80
+	// tablePadding is the number of columns a two column table adds around its cells.
+	tablePadding = 6
+	// minWrapWidth is the narrowest a table column is wrapped to, however narrow the terminal.
+	minWrapWidth = 20
+)
+
+const (
+	noColorFlag   = "no-color"
+	noColorEnvVar = "NO_COLOR"
+)
+
+// ANSI escape sequences used to style help
+const (
+	styleReset = "\033[0m"
+	styleBold  = "\033[1m"
+	styleRed   = "\033[31m"
+	styleCyan  = "\033[36m"
+)
+
+// helpFormat is how help and usage are laid out on the output of the CLI.
+type helpFormat struct {
+	// width is the number of columns text is wrapped at. 0 disables wrapping.
+	width int
+	// color enables ANSI styling.
+	color bool
+}
+
+// helpFormat returns the format of help written to the stdout of the CLI.
+// The width is the width of the terminal, the COLUMNS environment variable or 80.
+// Colors are used when stdout is a terminal, unless disabled with --no-color or NO_COLOR.
+func (c *CLI) helpFormat(noColor bool) helpFormat {
+	format := helpFormat{width: terminalWidth(c.stdout)}
+	if format.width <= 0 {
+		if columns, ok := c.lookupEnv("COLUMNS"); ok {
+			format.width, _ = strconv.Atoi(columns)
+		}
+	}
+	if format.width <= 0 {
+		format.width = defaultHelpWidth
+	}
+
+	// NO_COLOR only applies when it is set to a non-empty value
+	if value, ok := c.lookupEnv(noColorEnvVar); ok && value != "" {
+		noColor = true
+	}
+	format.color = !noColor && isTerminal(c.stdout)
+	return format
+}
+
+// style wraps the text in the given ANSI style when colors are enabled.
+func (f helpFormat) style(style string, text string) string {
+	if !f.color || text == "" {
+		return text
+	}
+	return style + text + styleReset
+}
+
+// wrap wraps the lines of the text at the width of the format.
+func (f helpFormat) wrap(text string) string {
+	return wrapText(text, f.width)
+}
+
+// wrapText breaks lines longer than width at spaces, keeping the indentation of the line.
+// Words longer than the width are not broken.
+func wrapText(text string, width int) string {
+	if width <= 0 {
+		return text
+	}
+	lines := []string{}
+	for _, line := range strings.Split(text, "\n") {
+		if tablewriter.DisplayWidth(line) <= width {
+			lines = append(lines, line)
+			continue
+		}
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		current := ""
+		for _, word := range strings.Fields(line) {
+			if current != "" && tablewriter.DisplayWidth(current)+1+tablewriter.DisplayWidth(word) > width {
+				lines = append(lines, current)
+				current = ""
+			}
+			if current == "" {
+				current = indent + word
+				continue
+			}
+			current += " " + word
+		}
+		lines = append(lines, current)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// cellWidth returns the number of columns the widest line of the cells takes on the terminal.
+func cellWidth(cells []string) int {
+	width := 0
+	for _, cell := range cells {
+		for _, line := range strings.Split(cell, "\n") {
+			width = max(width, tablewriter.DisplayWidth(line))
+		}
+	}
+	return width
+}
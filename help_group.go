@@ -0,0 +1,85 @@
+package cling
+
+import (
+	"slices"
+	"strings"
+)
+
+// HelpOrder is the order commands, flags and their groups are listed in the help.
+type HelpOrder int
+
+const (
+	// HelpOrderDeclaration lists commands, flags and groups in the order they are added.
+	HelpOrderDeclaration HelpOrder = iota
+	// HelpOrderAlphabetical lists commands, flags and groups sorted by name.
+	HelpOrderAlphabetical
+)
+
+// groupItems splits items into groups by their title and returns the titles in listing order.
+// Ungrouped items have an empty title and are always listed first.
+func groupItems[T any](items []T, order HelpOrder, title func(T) string, name func(T) string) ([]string, map[string][]T) {
+	titles := []string{}
+	groups := map[string][]T{}
+	for _, item := range items {
+		t := title(item)
+		if _, ok := groups[t]; !ok {
+			titles = append(titles, t)
+		}
+		groups[t] = append(groups[t], item)
+	}
+
+	if order == HelpOrderAlphabetical {
+		for _, group := range groups {
+			slices.SortStableFunc(group, func(a, b T) int {
+				return strings.Compare(name(a), name(b))
+			})
+		}
+		slices.Sort(titles)
+	}
+	if idx := slices.Index(titles, ""); idx > 0 {
+		titles = slices.Insert(slices.Delete(titles, idx, idx+1), 0, "")
+	}
+	return titles, groups
+}
+
+// commandGroupsData lists the commands in their groups, down to maxDepth levels (0 for the full tree).
+func (c *CLI) commandGroupsData(commands []*Command, maxDepth int) []CommandGroupData {
+	titles, groups := groupItems(
+		visibleCommands(commands),
+		c.helpOrder,
+		func(cmd *Command) string { return cmd.group },
+		func(cmd *Command) string { return cmd.name },
+	)
+	data := make([]CommandGroupData, 0, len(titles))
+	for _, title := range titles {
+		data = append(data, CommandGroupData{
+			Title:    title,
+			Commands: commandsData(groups[title], 0, maxDepth, c.helpOrder),
+		})
+	}
+	return data
+}
+
+// flagGroupsData lists the flags in their groups.
+func (c *CLI) flagGroupsData(flags []FlagData) []FlagGroupData {
+	titles, groups := groupItems(
+		flags,
+		c.helpOrder,
+		func(flag FlagData) string { return flag.Group },
+		func(flag FlagData) string { return flag.Name },
+	)
+	data := make([]FlagGroupData, 0, len(titles))
+	for _, title := range titles {
+		data = append(data, FlagGroupData{Title: title, Flags: groups[title]})
+	}
+	return data
+}
+
+// flattenCommandGroups returns the commands of all groups in listing order.
+func flattenCommandGroups(groups []CommandGroupData) []CommandData {
+	commands := []CommandData{}
+	for _, group := range groups {
+		commands = append(commands, group.Commands...)
+	}
+	return commands
+}
@@ -0,0 +1,317 @@
+package cling
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// UsageData is the data the usage template is executed with.
+// The usage is shown when the CLI is run without a command or with --help and no command.
+type UsageData struct {
+	// Name is the name of the CLI.
+	Name string
+	// Version is the version of the CLI.
+	Version string
+	// Description is the description of the CLI.
+	Description string
+	// LongDescription is the long description of the CLI.
+	LongDescription string
+	// Usage lists the ways the CLI can be invoked, like 'cli [command] [flags] [arguments]'.
+	Usage []string
+	// Commands lists the commands of the CLI, depth first.
+	Commands []CommandData
+	// CommandGroups lists the top level commands of the CLI and their children in their groups.
+	CommandGroups []CommandGroupData
+	// HelpTopics lists the help topics of the CLI.
+	HelpTopics []HelpTopicData
+	// FlagGroups lists the global flags of the CLI and the flags of its action.
+	FlagGroups []FlagGroupData
+	// Args lists the arguments of the action of the CLI.
+	Args []ArgData
+	// EnvVars lists the environment variables read by the flags of the action of the CLI.
+	EnvVars []EnvVarData
+}
+
+// HelpData is the data the help template is executed with.
+// The help is shown for a command with --help or 'help <command>'.
+type HelpData struct {
+	// CLIName is the name of the CLI.
+	CLIName string
+	// Name is the name of the command.
+	Name string
+	// Path lists the names of the commands from the top level command down to this one.
+	Path []string
+	// CommandPath is the name of the CLI followed by the path, like 'cli parent command'.
+	CommandPath string
+	// Aliases lists the alternative names of the command.
+	Aliases []string
+	// Description is the description of the command.
+	Description string
+	// LongDescription is the long description of the command.
+	LongDescription string
+	// Usage is the usage of the command, like 'cli parent command <arg> [flags]'.
+	Usage string
+	// Commands lists the children of the command, depth first.
+	Commands []CommandData
+	// CommandGroups lists the children of the command in their groups.
+	CommandGroups []CommandGroupData
+	// Examples lists the examples of the command.
+	Examples []ExampleData
+	// SeeAlso lists the command paths of related commands, like 'cli config set'.
+	SeeAlso []string
+	// Args lists the arguments of the command.
+	Args []ArgData
+	// FlagGroups lists the flags of the command.
+	FlagGroups []FlagGroupData
+	// EnvVars lists the environment variables read by the flags of the command.
+	EnvVars []EnvVarData
+}
+
+// CommandData describes a command in a list of commands.
+type CommandData struct {
+	// Name is the name of the command.
+	Name string
+	// Path is the path of the command relative to the listing command, like 'parent command'.
+	Path string
+	// Aliases lists the alternative names of the command.
+	Aliases []string
+	// Description is the description of the command.
+	Description string
+	// Group is the title of the group the command is listed under. It is empty for ungrouped commands.
+	Group string
+	// Depth is the nesting level of the command in the list, starting at 0.
+	Depth int
+}
+
+// CommandGroupData is a titled group of commands.
+type CommandGroupData struct {
+	// Title is the title of the group. It is empty for ungrouped commands.
+	Title string
+	// Commands lists the commands in the group and their children, depth first.
+	Commands []CommandData
+}
+
+// ExampleData describes an example of a command.
+type ExampleData struct {
+	// Description is the description of the example.
+	Description string
+	// CommandLine is the command line of the example, starting with the name of the CLI.
+	CommandLine string
+}
+
+// HelpTopicData describes a help topic.
+type HelpTopicData struct {
+	// Name is the name of the topic.
+	Name string
+	// Summary is the first line of the body of the topic.
+	Summary string
+	// Body is the content of the topic.
+	Body string
+}
+
+// FlagGroupData is a titled group of flags.
+type FlagGroupData struct {
+	// Title is the title of the group. It is empty for ungrouped flags.
+	Title string
+	// Flags lists the flags in the group.
+	Flags []FlagData
+}
+
+// FlagData describes a flag.
+type FlagData struct {
+	// Name is the name of the flag without dashes.
+	Name string
+	// Shorthand is the single letter alias of the flag without the dash, if any.
+	Shorthand string
+	// Description is the description of the flag.
+	Description string
+	// Group is the title of the group the flag is listed under. It is empty for ungrouped flags.
+	Group string
+	// Usage is the description followed by the constraint, the default and the environment variables.
+	Usage string
+	// Constraint describes the values the validator of the flag accepts.
+	Constraint string
+	// Required reports whether the flag is required.
+	Required bool
+	// HasDefault reports whether the flag has a default value.
+	HasDefault bool
+	// Default is the default value in its command line form.
+	Default string
+	// EnvVars lists the environment variables the flag is read from.
+	EnvVars []string
+}
+
+// ArgData describes an argument.
+type ArgData struct {
+	// Name is the name of the argument.
+	Name string
+	// Description is the description of the argument.
+	Description string
+	// LongDescription is the long description of the argument.
+	LongDescription string
+	// Usage is the description followed by the constraint and the default.
+	Usage string
+	// Constraint describes the values the validator of the argument accepts.
+	Constraint string
+	// Required reports whether the argument is required.
+	Required bool
+	// HasDefault reports whether the argument has a default value.
+	HasDefault bool
+	// Default is the default value in its command line form.
+	Default string
+}
+
+// EnvVarData describes an environment variable read by a flag.
+type EnvVarData struct {
+	// Name is the name of the environment variable.
+	Name string
+	// Flag is the name of the flag the variable is read into.
+	Flag string
+}
+
+// DefaultUsageTemplate is the template used to render the usage of the CLI.
+// It is executed with UsageData.
+const DefaultUsageTemplate = `{{range $i, $usage := .Usage}}{{if eq $i 0}}{{heading "Usage:"}} {{else}}       {{end}}{{$usage}}
+{{end}}{{range .CommandGroups}}
+{{heading (or .Title "Available") "Commands:"}}
+{{commandTable .Commands}}{{end}}{{if .HelpTopics}}
+{{heading "Help Topics:"}}
+{{topicTable .HelpTopics}}{{end}}{{if .Args}}
+{{heading "Arguments:"}}
+{{argTable .Args}}{{end}}{{range .FlagGroups}}
+{{heading .Title "Flags:"}}
+{{flagTable .Flags}}{{end}}
+Use "{{.Name}} [command] --help" for more information about a command.
+`
+
+// DefaultHelpTemplate is the template used to render the help of a command.
+// It is executed with HelpData.
+const DefaultHelpTemplate = `{{wrap .LongDescription}}
+{{heading "Usage:"}}
+  {{.Usage}}
+{{if .Aliases}}
+{{heading "Aliases:"}}
+  {{join .Aliases ", "}}
+{{end}}{{if .Examples}}
+{{heading "Examples:"}}
+{{range $i, $example := .Examples}}{{if $i}}
+{{end}}{{if .Description}}  # {{.Description}}
+{{end}}  {{.CommandLine}}
+{{end}}{{end}}{{if .SeeAlso}}
+{{heading "See Also:"}}
+{{range .SeeAlso}}  {{.}}
+{{end}}{{end}}{{range .CommandGroups}}
+{{heading (or .Title "Available") "Commands:"}}
+{{commandTable .Commands}}
+{{end}}{{if .Args}}
+{{heading "Arguments:"}}
+{{argTable .Args}}{{end}}{{range .FlagGroups}}
+{{heading .Title "Flags:"}}
+{{flagTable .Flags}}
+
+{{end}}{{if .Commands}}Use "{{.CommandPath}} [command] --help" for more information about a command.
+{{end}}`
+
+// templateFuncs are the functions available to help and usage templates:
+//
+//	indent n              returns 2*n spaces
+//	join list separator   joins a list of strings
+//	heading texts...      joins the non-empty texts with spaces and styles them as a heading
+//	wrap text             wraps the text at the width of the terminal
+//	flagTable flags       renders flags as an aligned table of names and usages
+//	argTable args         renders arguments as an aligned table of names and usages
+//	topicTable topics     renders help topics as an aligned table of names and summaries
+//	commandTable commands renders commands as an aligned table of names, indented by depth, and descriptions
+func templateFuncs(format helpFormat) template.FuncMap {
+	return template.FuncMap{
+		"indent": func(depth int) string {
+			return strings.Repeat("  ", depth)
+		},
+		"join": strings.Join,
+		"heading": func(texts ...string) string {
+			return format.style(styleBold, strings.Join(slices.DeleteFunc(texts, func(text string) bool { return text == "" }), " "))
+		},
+		"wrap": format.wrap,
+		"flagTable": func(flags []FlagData) string {
+			rows := make([][]string, 0, len(flags))
+			for _, flag := range flags {
+				name := "--" + flag.Name
+				if flag.Shorthand != "" {
+					name = fmt.Sprintf("%s, -%s", name, flag.Shorthand)
+				}
+				usage := flag.Usage
+				if flag.Required {
+					usage = strings.TrimSpace(fmt.Sprintf("%s %s", usage, format.style(styleRed, "(required)")))
+				}
+				rows = append(rows, []string{format.style(styleCyan, name), usage})
+			}
+			buff := bytes.NewBuffer(nil)
+			writeTable(buff, rows, format.width)
+			return buff.String()
+		},
+		"argTable": func(args []ArgData) string {
+			rows := make([][]string, 0, len(args))
+			for _, arg := range args {
+				name := fmt.Sprintf("[%s]", arg.Name)
+				if arg.Required {
+					name = fmt.Sprintf("<%s>", arg.Name)
+				}
+				rows = append(rows, []string{format.style(styleCyan, name), arg.Usage})
+			}
+			buff := bytes.NewBuffer(nil)
+			writeTable(buff, rows, format.width)
+			return buff.String()
+		},
+		"topicTable": func(topics []HelpTopicData) string {
+			rows := make([][]string, 0, len(topics))
+			for _, topic := range topics {
+				rows = append(rows, []string{topic.Name, topic.Summary})
+			}
+			buff := bytes.NewBuffer(nil)
+			writeTable(buff, rows, format.width)
+			return buff.String()
+		},
+		"commandTable": func(commands []CommandData) string {
+			rows := make([][]string, 0, len(commands))
+			for _, cmd := range commands {
+				rows = append(rows, []string{strings.Repeat("  ", cmd.Depth) + cmd.Name, cmd.Description})
+			}
+			buff := bytes.NewBuffer(nil)
+			writeTable(buff, rows, format.width)
+			return buff.String()
+		},
+	}
+}
+
+// writeTable writes the rows as borderless aligned columns,
+// wrapping the last column so that the rows fit in width columns. A width of 0 disables wrapping.
+func writeTable(w io.Writer, rows [][]string, width int) {
+	if width > 0 && len(rows) > 0 {
+		names := make([]string, 0, len(rows))
+		for _, row := range rows {
+			names = append(names, row[0])
+		}
+		wrapWidth := max(width-cellWidth(names)-tablePadding, minWrapWidth)
+		for _, row := range rows {
+			row[len(row)-1] = wrapText(row[len(row)-1], wrapWidth)
+		}
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetBorder(false)
+	table.SetColumnSeparator("")
+	// the table wraps at a fixed width and trims indentation, so the rows are wrapped above
+	table.SetAutoWrapText(false)
+	table.AppendBulk(rows)
+	table.Render()
+}
+
+func parseTemplate(name string, text string, format helpFormat) (*template.Template, error) {
+	return template.New(name).Funcs(templateFuncs(format)).Parse(text)
+}
@@ -0,0 +1,33 @@
+package cling
+
+import (
+	"context"
+	"time"
+)
+
+// HookContext describes the run of a command to its hooks.
+// Hooks get it from their context with HookContextFromContext.
+// It is filled in as the run goes on, so post-run hooks see how the action went.
+type HookContext struct {
+	// Command is the command that runs.
+	Command *Command
+	// Invocation describes how the command was invoked, with the flags and positionals parsed from the command line.
+	Invocation *Invocation
+	// Destination is the struct the action last populated with Hydrate. It is nil if the action did not call Hydrate.
+	Destination any
+	// Err is the error the run failed with, if any. It is set before the post-run hooks run.
+	Err error
+	// Duration is how long the action ran. It is set before the post-run hooks run.
+	Duration time.Duration
+}
+
+func contextWithHookContext(ctx context.Context, hookContext *HookContext) context.Context {
+	return context.WithValue(ctx, ContextKeyHook, hookContext)
+}
+
+// HookContextFromContext returns the hook context of the running command.
+// It returns false if the context is not derived from a CLIng supplied context.
+func HookContextFromContext(ctx context.Context) (*HookContext, bool) {
+	hookContext, ok := ctx.Value(ContextKeyHook).(*HookContext)
+	return hookContext, ok
+}
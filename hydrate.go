@@ -3,8 +3,8 @@ package cling
 import (
 	"context"
 	"fmt"
-	"os"
 	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -36,7 +36,7 @@ func Hydrate[T any](ctx context.Context, argArguments []string, destination *T)
 
 	// make sure that we have targets for all required
 	for _, cmdFlag := range cmd.flags {
-		if !cmdFlag.isRequired() {
+		if !cmdFlag.IsRequired() {
 			continue
 		}
 		if _, found := targets[cmdFlag.Name()]; !found {
@@ -45,7 +45,7 @@ func Hydrate[T any](ctx context.Context, argArguments []string, destination *T)
 	}
 
 	for _, cmdArg := range cmd.arguments {
-		if !cmdArg.isRequired() {
+		if !cmdArg.IsRequired() {
 			continue
 		}
 		if _, found := targets[cmdArg.Name()]; !found {
@@ -55,105 +55,112 @@ func Hydrate[T any](ctx context.Context, argArguments []string, destination *T)
 
 	destVal := reflect.ValueOf(destination).Elem()
 
-	if err := hydrateFlags(cmd, argFlags, destVal, targets); err != nil {
+	// prompter is nil unless the CLI runs interactively
+	prompter, _ := prompterFromContext(ctx)
+
+	if err := hydrateFlags(ctx, cmd, argFlags, destVal, targets, prompter); err != nil {
 		return err
 	}
 
-	if err := hydrateArgs(cmd, argArguments, destVal, targets); err != nil {
+	if err := hydrateArgs(ctx, cmd, argArguments, destVal, targets, prompter); err != nil {
 		return err
 	}
 
+	// let post-run hooks see the values the action ran with
+	if hookContext, ok := HookContextFromContext(ctx); ok {
+		hookContext.Destination = destination
+	}
+
 	return nil
 }
 
-func hydrateArgs(cmd *Command, args []string, destination reflect.Value, targets configTargets) error {
+func hydrateArgs(ctx context.Context, cmd *Command, args []string, destination reflect.Value, targets configTargets, prompter *prompter) error {
 	// verify we have at least the required number of arguments
 	requiredArguments := 0
 	for _, argument := range cmd.arguments {
-		if argument.isRequired() {
+		if argument.IsRequired() {
 			requiredArguments++
 		}
 	}
 
-	if len(args) < requiredArguments {
+	if len(args) < requiredArguments && prompter == nil {
 		return errors.Errorf("missing at least one required argument. need '%d' - got '%d'", requiredArguments, len(args))
 	}
 
 	for idx, argument := range cmd.arguments {
-		validator := NoOpValidator()
-		if flagWithValidator, ok := argument.(CmdInputWithDefaultAndValidator[any]); ok {
-			validator = flagWithValidator.getValidator().(Validator[any])
-		}
-
 		target, ok := targets[argument.Name()]
-		field := destination.Field(target.structIdx)
-		if idx < len(args) {
-			if !ok {
+		if !ok {
+			if idx < len(args) {
 				return errors.Errorf("could not find target for '%s'", argument.Name())
 			}
-			if err := setFieldFromString(field, args[idx], validator); err != nil {
+			continue
+		}
+		field := destination.Field(target.structIdx)
+		pipeline := pipelineFor(argument)
+
+		values := []string{}
+		switch {
+		case idx == len(cmd.arguments)-1 && field.Kind() == reflect.Slice && idx < len(args):
+			// a trailing slice argument takes all remaining positionals
+			values = args[idx:]
+		case idx < len(args):
+			values = []string{args[idx]}
+		case argument.HasDefault():
+			values = defaultValueStrings(argument)
+		case argument.IsRequired() && prompter != nil:
+			if err := prompter.promptInto(ctx, argument, field, pipeline); err != nil {
 				return errors.Wrapf(err, "failed to set argument '%s'", argument.Name())
 			}
-		} else {
-			// go with default
-			val := fmt.Sprint(argument.getDefault())
-			if ok {
-				// put in the default
-				if err := setFieldFromString(field, val, validator); err != nil {
-					return errors.Wrapf(err, "failed to set argument '%s'", argument.Name())
-				}
-			}
+			continue
+		}
+		if len(values) == 0 {
+			continue
+		}
+
+		if err := pipeline.setField(ctx, field, values); err != nil {
+			return errors.Wrapf(err, "failed to set argument '%s'", argument.Name())
 		}
 	}
 
 	return nil
 }
 
-func hydrateFlags(cmd *Command, flags map[string][]string, destination reflect.Value, targets configTargets) error {
+func hydrateFlags(ctx context.Context, cmd *Command, flags map[string][]string, destination reflect.Value, targets configTargets, prompter *prompter) error {
+	lookupEnv := envLookupFromContext(ctx)
 	// get defined flags
 	for _, flag := range cmd.flags {
 		name := flag.Name()
 		flagValues, definedInFlags := flags[name]
 
-		validator := NoOpValidator()
-		if flagWithValidator, ok := flag.(ValidatorProvider); ok && flagWithValidator.getValidator() != nil {
-			validator = flagWithValidator.getValidator()
-		}
+		pipeline := pipelineFor(flag)
 
-		if !definedInFlags && flag.hasDefault() {
-			// get the default
-			def := flag.getDefault()
-			// run it through the validator
-			if err := validator.Validate(def); err != nil {
-				return errors.Wrapf(err, "cannot set invalid default '%v' for '%s'", def, name)
-			}
-			// if def is a slice
-			if reflect.TypeOf(def).Kind() == reflect.Slice {
-				flagValues = []string{}
-				for i := 0; i < reflect.ValueOf(def).Len(); i++ {
-					flagValues = append(flagValues, fmt.Sprint(reflect.ValueOf(def).Index(i).Interface()))
-				}
-			} else {
-				flagValues = []string{fmt.Sprint(def)}
-			}
+		fromDefault := false
+		if !definedInFlags && flag.HasDefault() {
+			flagValues = defaultValueStrings(flag)
+			fromDefault = true
 		}
 
 		// if not defined in flags and has env sources
-		if !definedInFlags && len(flag.envSources()) > 0 {
+		if !definedInFlags && len(flag.EnvSources()) > 0 {
 			// try to populate from env
-			for _, envKey := range flag.envSources() {
-				if val, ok := os.LookupEnv(envKey); ok {
+			for _, envKey := range flag.EnvSources() {
+				if val, ok := lookupEnv(envKey); ok {
 					flagValues = []string{val}
+					fromDefault = false
 				}
 			}
 		}
 
-		if (flag.isRequired()) && (len(flagValues) == 0) {
+		if (flag.IsRequired()) && (len(flagValues) == 0) && prompter == nil {
 			return errors.Errorf("missing required flag '%s'", flag.Name())
 		}
 
-		target, definedInFlags := targets[name]
-		if !definedInFlags {
+		target, hasTarget := targets[name]
+		if !hasTarget && cmd.confirmation != "" && name == confirmationFlag {
+			// the confirmation flag is handled by the CLI - it is only set if the destination asks for it
+			continue
+		}
+		if !hasTarget {
 			return errors.Errorf("could not find target for '%s'", name)
 		}
 		field := destination.Field(target.structIdx)
@@ -164,48 +171,184 @@ func hydrateFlags(cmd *Command, flags map[string][]string, destination reflect.V
 		if !field.CanSet() {
 			return errors.Errorf("field for flag '%s' cannot be set", name)
 		}
-		if field.Kind() == reflect.Slice {
-			for _, valueStr := range flagValues {
-				if err := setFieldFromString(field, valueStr, validator); err != nil {
+		if len(flagValues) == 0 {
+			if flag.IsRequired() {
+				if err := prompter.promptInto(ctx, flag, field, pipeline); err != nil {
 					return errors.Wrapf(err, "failed to set flag '%s'", name)
 				}
 			}
-		} else {
-			valueStr := flagValues[0]
-			if err := setFieldFromString(field, valueStr, validator); err != nil {
-				return errors.Wrapf(err, "failed to set flag '%s'", name)
+			continue
+		}
+		if err := pipeline.setField(ctx, field, flagValues); err != nil {
+			if fromDefault {
+				return errors.Wrapf(err, "cannot set invalid default '%v' for '%s'", flag.Default(), name)
 			}
+			return errors.Wrapf(err, "failed to set flag '%s'", name)
 		}
 	}
 
 	return nil
 }
 
+// defaultValueStrings returns the default value of the input in its command line form.
+func defaultValueStrings(input CmdInput) []string {
+	def := input.Default()
+	if reflect.TypeOf(def).Kind() != reflect.Slice {
+		return []string{fmt.Sprint(def)}
+	}
+	values := []string{}
+	for i := 0; i < reflect.ValueOf(def).Len(); i++ {
+		values = append(values, fmt.Sprint(reflect.ValueOf(def).Index(i).Interface()))
+	}
+	return values
+}
+
+// argToken is an argument of the command line as it was given: a flag with its value, or a positional.
+type argToken struct {
+	// flag is set for flags
+	flag *FlagValue
+	// positional is the value of a positional
+	positional string
+	// raw lists the arguments the token was parsed from, like ['--name', 'value'] or ['--name=value']
+	raw []string
+}
+
+// parseArguments splits the arguments into the values of the flags by name and the positionals.
+// Built-in short flags are expanded to their long names.
 func parseArguments(args []string) (flags map[string][]string, arguments []string) {
-	flags = make(map[string][]string)
-	arguments = make([]string, 0)
+	tokens := tokenizeArguments(args)
+	expandShortFlags(tokens)
+	return flagsByName(tokenFlags(tokens)), tokenPositionals(tokens)
+}
+
+// tokenizeArguments splits the arguments into tokens, in order.
+// Joining the raw arguments of the tokens gives back the arguments.
+func tokenizeArguments(args []string) []argToken {
+	tokens := make([]argToken, 0, len(args))
 	for i := 0; i < len(args); i++ {
 		arg := args[i]
-		if strings.HasPrefix(arg, "--") {
+		if isShortFlag(arg) {
+			// short flags are switches and never take a value
+			tokens = append(tokens, argToken{flag: &FlagValue{Name: strings.TrimPrefix(arg, "-")}, raw: []string{arg}})
+		} else if strings.HasPrefix(arg, "--") {
 			parts := strings.SplitN(arg, "=", 2)
 			flagName := strings.TrimPrefix(parts[0], "--")
 			if len(parts) == 2 {
 				// Handle --flag=value
-				flags[flagName] = append(flags[flagName], parts[1])
-			} else if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
+				tokens = append(tokens, argToken{flag: &FlagValue{Name: flagName, Value: parts[1]}, raw: []string{arg}})
+			} else if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") && !isBuiltinSwitch(flagName) {
 				// Handle --flag value
-				flags[flagName] = append(flags[flagName], args[i+1])
+				tokens = append(tokens, argToken{flag: &FlagValue{Name: flagName, Value: args[i+1]}, raw: []string{arg, args[i+1]}})
 				i++ // Skip the next element as it is already used as a value
 			} else {
 				// Handle flags without values, assign an empty string or a default value
-				flags[flagName] = append(flags[flagName], "")
+				tokens = append(tokens, argToken{flag: &FlagValue{Name: flagName}, raw: []string{arg}})
 			}
 		} else {
-			arguments = append(arguments, arg)
+			tokens = append(tokens, argToken{positional: arg, raw: []string{arg}})
+		}
+	}
+	return tokens
+}
+
+// tokenFlags returns the flags of the tokens, in order.
+func tokenFlags(tokens []argToken) []FlagValue {
+	flags := []FlagValue{}
+	for _, token := range tokens {
+		if token.flag != nil {
+			flags = append(flags, *token.flag)
+		}
+	}
+	return flags
+}
+
+// tokenPositionals returns the positionals of the tokens, in order.
+func tokenPositionals(tokens []argToken) []string {
+	positionals := []string{}
+	for _, token := range tokens {
+		if token.flag == nil {
+			positionals = append(positionals, token.positional)
+		}
+	}
+	return positionals
+}
+
+// tokenArgs returns the arguments the tokens were parsed from, in order.
+func tokenArgs(tokens []argToken) []string {
+	args := []string{}
+	for _, token := range tokens {
+		args = append(args, token.raw...)
+	}
+	return args
+}
+
+// flagsByName groups the values of the flags by the name of the flag.
+func flagsByName(flags []FlagValue) map[string][]string {
+	byName := make(map[string][]string)
+	for _, flag := range flags {
+		byName[flag.Name] = append(byName[flag.Name], flag.Value)
+	}
+	return byName
+}
+
+// builtinSwitch is a flag the CLI handles itself.
+type builtinSwitch struct {
+	name      string
+	shorthand string
+	// description is shown in the global flags of the usage. Switches without one are not listed.
+	description string
+}
+
+// builtinSwitches are the flags the CLI handles itself. They never take the next argument as their value,
+// so in '--yes x' the 'x' stays a positional. A value can still be given as in '--yes=false'.
+var builtinSwitches = []builtinSwitch{
+	{name: "help", shorthand: "h", description: "Show help information"},
+	{name: "version", description: "Show version information"},
+	{name: noColorFlag, description: "Disable colored output"},
+	{name: "no-input", description: "Disable interactive prompts"},
+	// listed with the flags of the commands that ask for confirmation
+	{name: confirmationFlag, shorthand: "y"},
+	// not listed in the help
+	{name: schemaFlag},
+}
+
+// isBuiltinSwitch reports whether the flag is one of the built-in switches.
+func isBuiltinSwitch(name string) bool {
+	return slices.ContainsFunc(builtinSwitches, func(s builtinSwitch) bool { return s.name == name })
+}
+
+// shortFlagAliases maps built-in short flags to their long names.
+var shortFlagAliases = func() map[string]string {
+	aliases := map[string]string{}
+	for _, s := range builtinSwitches {
+		if s.shorthand != "" {
+			aliases[s.shorthand] = s.name
+		}
+	}
+	return aliases
+}()
+
+// expandShortFlags renames built-in short flags to their long names.
+// The raw arguments are kept, as they are expanded again when they are parsed.
+func expandShortFlags(tokens []argToken) {
+	for _, token := range tokens {
+		if token.flag == nil {
+			continue
+		}
+		if long, ok := shortFlagAliases[token.flag.Name]; ok {
+			token.flag.Name = long
 		}
 	}
+}
 
-	return flags, arguments
+// isShortFlag reports whether the argument is one of the built-in short flags like '-y'.
+// Other single dash arguments, like negative numbers, are positionals.
+func isShortFlag(arg string) bool {
+	if !strings.HasPrefix(arg, "-") || strings.HasPrefix(arg, "--") {
+		return false
+	}
+	_, ok := shortFlagAliases[strings.TrimPrefix(arg, "-")]
+	return ok
 }
 
 func extractConfigTargets(config any) (targets map[string]configTarget, e error) {
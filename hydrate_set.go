@@ -1,71 +1,157 @@
 package cling
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"strconv"
 	"strings"
+
+	"github.com/pkg/errors"
 )
 
-func setFieldFromString(field reflect.Value, valueStr string, validator Validator[any]) error {
+// valuePipeline is the sequence of steps a raw value goes through before it is set on a field:
+// parsing, transformation, element validation (for slices) and validation.
+type valuePipeline struct {
+	transformer      transformerAny
+	validator        validatorAny
+	elementValidator validatorAny
+}
+
+// pipelineFor returns the value pipeline of the input.
+// Steps that are not configured on the input are no-ops.
+func pipelineFor(input CmdInput) *valuePipeline {
+	pipeline := &valuePipeline{
+		transformer:      &noOpTransformer{},
+		validator:        NoOpValidator(),
+		elementValidator: NoOpValidator(),
+	}
+	if provider, ok := input.(TransformerProvider); ok && provider.getTransformer() != nil {
+		pipeline.transformer = provider.getTransformer()
+	}
+	if provider, ok := input.(ValidatorProvider); ok && provider.getValidator() != nil {
+		pipeline.validator = provider.getValidator()
+	}
+	if provider, ok := input.(elementValidatorProvider); ok && provider.getElementValidator() != nil {
+		pipeline.elementValidator = provider.getElementValidator()
+	}
+	return pipeline
+}
+
+// setField parses the given values into a value of the field's type,
+// runs it through the pipeline, and sets it on the field.
+// Slice fields consume all values, splitting each on commas.
+// Other fields consume only the first value.
+func (p *valuePipeline) setField(ctx context.Context, field reflect.Value, valueStrs []string) error {
 	var value any
 	var err error
 
-	switch field.Kind() {
-	case reflect.String:
-		value, err = parseString(valueStr)
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		value, err = parseInt(valueStr, field.Type().Bits())
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		value, err = parseUint(valueStr, field.Type().Bits())
-	case reflect.Bool:
-		if valueStr == "" {
-			// just having the flag means it's true
-			valueStr = "true"
-		}
-		value, err = parseBool(valueStr)
-	case reflect.Float32, reflect.Float64:
-		value, err = parseFloat(valueStr, field.Type().Bits())
-	case reflect.Slice:
-		if strings.Contains(valueStr, ",") {
-			values := strings.Split(valueStr, ",")
-			for _, value := range values {
-				if err := setSlice(field, value, validator); err != nil {
-					return err
-				}
-			}
-			return nil
-		} else {
-			return setSlice(field, valueStr, validator)
-		}
-	default:
-		return fmt.Errorf("unsupported field type: %s", field.Type().Kind())
+	if field.Kind() == reflect.Slice {
+		value, err = parseSlice(field.Type(), valueStrs)
+	} else {
+		value, err = parseValue(field.Type(), valueStrs[0])
 	}
-
 	if err != nil {
 		return err
 	}
 
-	if err := runValidator(value, validator); err != nil {
+	if value, err = p.transformer.Transform(ctx, value); err != nil {
 		return err
 	}
 
-	field.Set(reflect.ValueOf(value).Convert(field.Type()))
+	if field.Kind() == reflect.Slice {
+		elements := reflect.ValueOf(value)
+		for i := 0; i < elements.Len(); i++ {
+			if err := runValidator(elements.Index(i).Interface(), p.elementValidator); err != nil {
+				return errors.Wrapf(err, "element %d", i)
+			}
+		}
+	}
+
+	if err := runValidator(value, p.validator); err != nil {
+		return err
+	}
+
+	// the transformer returns values of the type of the input, which may differ from the type of the field
+	converted, ok := convertValue(value, field.Type())
+	if !ok {
+		return errors.Errorf("invalid type: expected %s, got %T", field.Type(), value)
+	}
+	field.Set(reflect.ValueOf(converted))
 	return nil
 }
 
-func setSlice(field reflect.Value, valueStr string, validators ...Validator[any]) error {
-	elemType := field.Type().Elem()
+// convertTo converts the value to S, so that inputs can be bound to fields of a related type,
+// like an int input to an int64 field.
+func convertTo[S any](value any) (S, bool) {
+	if val, ok := value.(S); ok {
+		return val, true
+	}
+	converted, ok := convertValue(value, reflect.TypeOf((*S)(nil)).Elem())
+	if !ok {
+		return *new(S), false
+	}
+	return converted.(S), true
+}
+
+// convertValue converts the value to the given type, element by element for slices.
+// It reports false if the value cannot be converted without changing it, like 1.5 to an int or 300 to an int8.
+func convertValue(value any, valueType reflect.Type) (any, bool) {
+	v := reflect.ValueOf(value)
+	if !v.IsValid() {
+		return nil, false
+	}
+	if v.Type() == valueType {
+		return value, true
+	}
+	if v.Kind() == reflect.Slice && valueType.Kind() == reflect.Slice {
+		converted := reflect.MakeSlice(valueType, v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			elem, ok := convertValue(v.Index(i).Interface(), valueType.Elem())
+			if !ok {
+				return nil, false
+			}
+			converted.Index(i).Set(reflect.ValueOf(elem))
+		}
+		return converted.Interface(), true
+	}
+	if !v.CanConvert(valueType) || !reflect.Zero(valueType).CanConvert(v.Type()) {
+		return nil, false
+	}
+	converted := v.Convert(valueType)
+	if !converted.Convert(v.Type()).Equal(v) {
+		return nil, false
+	}
+	return converted.Interface(), true
+}
+
+func parseSlice(sliceType reflect.Type, valueStrs []string) (any, error) {
+	elemType := sliceType.Elem()
+	slice := reflect.MakeSlice(sliceType, 0, len(valueStrs))
+	for _, valueStr := range valueStrs {
+		for _, part := range strings.Split(valueStr, ",") {
+			value, err := parseValue(elemType, part)
+			if err != nil {
+				return nil, err
+			}
+			slice = reflect.Append(slice, reflect.ValueOf(value))
+		}
+	}
+	return slice.Interface(), nil
+}
+
+// parseValue parses the given string into a value of the given type.
+func parseValue(valueType reflect.Type, valueStr string) (any, error) {
 	var value any
 	var err error
 
-	switch elemType.Kind() {
+	switch valueType.Kind() {
 	case reflect.String:
 		value, err = parseString(valueStr)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		value, err = parseInt(valueStr, elemType.Bits())
+		value, err = parseInt(valueStr, valueType.Bits())
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		value, err = parseUint(valueStr, elemType.Bits())
+		value, err = parseUint(valueStr, valueType.Bits())
 	case reflect.Bool:
 		if valueStr == "" {
 			// just having the flag means it's true
@@ -73,21 +159,16 @@ func setSlice(field reflect.Value, valueStr string, validators ...Validator[any]
 		}
 		value, err = parseBool(valueStr)
 	case reflect.Float32, reflect.Float64:
-		value, err = parseFloat(valueStr, elemType.Bits())
+		value, err = parseFloat(valueStr, valueType.Bits())
 	default:
-		return fmt.Errorf("unsupported slice element type: %s", elemType.Kind())
+		return nil, fmt.Errorf("unsupported field type: %s", valueType.Kind())
 	}
 
 	if err != nil {
-		return err
-	}
-
-	if err := runValidator(value, validators...); err != nil {
-		return err
+		return nil, err
 	}
 
-	field.Set(reflect.Append(field, reflect.ValueOf(value).Convert(elemType)))
-	return nil
+	return reflect.ValueOf(value).Convert(valueType).Interface(), nil
 }
 
 func parseString(valueStr string) (string, error) {
@@ -110,7 +191,7 @@ func parseFloat(valueStr string, bits int) (float64, error) {
 	return strconv.ParseFloat(valueStr, bits)
 }
 
-func runValidator(value any, validators ...Validator[any]) error {
+func runValidator(value any, validators ...validatorAny) error {
 	for _, validator := range validators {
 		if err := validator.Validate(value); err != nil {
 			return err
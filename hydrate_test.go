@@ -1 +1,291 @@
 package cling
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestHydrateTransform(t *testing.T) {
+	type config struct {
+		Level string   `cling-name:"level"`
+		Tags  []string `cling-name:"tags"`
+		Name  string   `cling-name:"name"`
+	}
+
+	cmd := NewCommand("cmd", action).
+		WithFlag(
+			NewStringCmdInput("level").
+				WithDefault(" INFO ").
+				WithTransform(NewTrimSpaceTransformer()).
+				WithTransform(NewEnumCanonicalTransformer("debug", "info")).
+				WithValidator(NewEnumValidator("debug", "info")).
+				AsFlag(),
+		).
+		WithFlag(
+			NewCmdSliceInput[string]("tags").
+				WithDefault([]string{}).
+				WithTransform(func(ctx context.Context, value []string) ([]string, error) {
+					return append(value, "extra"), nil
+				}).
+				AsFlag(),
+		).
+		WithArgument(
+			NewStringCmdInput("name").
+				WithTransform(NewLowercaseTransformer()).
+				Required().
+				AsArgument(),
+		)
+
+	cfg := &config{}
+	ctx := contextWithCommand(context.Background(), cmd)
+	if err := Hydrate(ctx, []string{"NAME", "--tags", "a,b"}, cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Level != "info" {
+		t.Fatalf("expected transformed default 'info', got '%s'", cfg.Level)
+	}
+	if len(cfg.Tags) != 3 || cfg.Tags[2] != "extra" {
+		t.Fatalf("expected transformed slice, got %v", cfg.Tags)
+	}
+	if cfg.Name != "name" {
+		t.Fatalf("expected transformed argument 'name', got '%s'", cfg.Name)
+	}
+}
+
+func TestExpandPathTransformer(t *testing.T) {
+	root := t.TempDir()
+	ctx := contextWithEnvLookup(context.Background(), func(key string) (string, bool) {
+		return root, key == "CLING_TEST_ROOT"
+	})
+	got, err := NewExpandPathTransformer()(ctx, "$CLING_TEST_ROOT/data")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := filepath.Join(root, "data"); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+type hydrateConfig struct {
+	Str     string    `cling-name:"str"`
+	Int     int       `cling-name:"int"`
+	Int64   int64     `cling-name:"int64"`
+	Uint    uint      `cling-name:"uint"`
+	Float   float64   `cling-name:"float"`
+	Bool    bool      `cling-name:"bool"`
+	Strs    []string  `cling-name:"strs"`
+	Ints    []int     `cling-name:"ints"`
+	Int64s  []int64   `cling-name:"int64s"`
+	Floats  []float64 `cling-name:"floats"`
+	Arg     string    `cling-name:"arg"`
+	IntArg  int       `cling-name:"intarg"`
+	RestArg []int     `cling-name:"rest"`
+}
+
+func TestHydrateInputKinds(t *testing.T) {
+	tests := []struct {
+		name    string
+		cmd     *Command
+		args    []string
+		env     map[string]string
+		check   func(cfg *hydrateConfig) bool
+		wantErr bool
+	}{
+		{
+			name:  "string flag",
+			cmd:   NewCommand("cmd", action).WithFlag(NewStringCmdInput("str").Required().AsFlag()),
+			args:  []string{"--str", "value"},
+			check: func(cfg *hydrateConfig) bool { return cfg.Str == "value" },
+		},
+		{
+			name:  "int flag",
+			cmd:   NewCommand("cmd", action).WithFlag(NewIntCmdInput("int").Required().AsFlag()),
+			args:  []string{"--int=42"},
+			check: func(cfg *hydrateConfig) bool { return cfg.Int == 42 },
+		},
+		{
+			name:  "int flag into int64 field",
+			cmd:   NewCommand("cmd", action).WithFlag(NewIntCmdInput("int64").Required().AsFlag()),
+			args:  []string{"--int64", "-7"},
+			check: func(cfg *hydrateConfig) bool { return cfg.Int64 == -7 },
+		},
+		{
+			name:  "int flag validator and transform into int64 field",
+			cmd:   NewCommand("cmd", action).WithFlag(NewIntCmdInput("int64").WithValidator(NewIntRangeValidator(1, 10)).WithTransform(func(ctx context.Context, v int) (int, error) { return v * 2, nil }).Required().AsFlag()),
+			args:  []string{"--int64", "4"},
+			check: func(cfg *hydrateConfig) bool { return cfg.Int64 == 8 },
+		},
+		{
+			name:    "int flag validator into int64 field",
+			cmd:     NewCommand("cmd", action).WithFlag(NewIntCmdInput("int64").WithValidator(NewIntRangeValidator(1, 10)).Required().AsFlag()),
+			args:    []string{"--int64", "11"},
+			wantErr: true,
+		},
+		{
+			name: "int slice flag element validator into int64 slice field",
+			cmd: NewCommand("cmd", action).WithFlag(
+				NewCmdSliceInput[int]("int64s").WithElementValidator(NewIntRangeValidator(1, 10)).WithDefault([]int{}).AsFlag(),
+			),
+			args:  []string{"--int64s", "1,2"},
+			check: func(cfg *hydrateConfig) bool { return len(cfg.Int64s) == 2 && cfg.Int64s[1] == 2 },
+		},
+		{
+			name:    "uint flag rejects negative",
+			cmd:     NewCommand("cmd", action).WithFlag(NewIntCmdInput("uint").Required().AsFlag()),
+			args:    []string{"--uint", "-1"},
+			wantErr: true,
+		},
+		{
+			name:  "float flag",
+			cmd:   NewCommand("cmd", action).WithFlag(NewStringCmdInput("float").Required().AsFlag()),
+			args:  []string{"--float", "1.5"},
+			check: func(cfg *hydrateConfig) bool { return cfg.Float == 1.5 },
+		},
+		{
+			name:  "bool flag without value",
+			cmd:   NewCommand("cmd", action).WithFlag(NewBoolCmdInput("bool").WithDefault(false).AsFlag()),
+			args:  []string{"--bool"},
+			check: func(cfg *hydrateConfig) bool { return cfg.Bool },
+		},
+		{
+			name:  "bool flag default",
+			cmd:   NewCommand("cmd", action).WithFlag(NewBoolCmdInput("bool").WithDefault(true).AsFlag()),
+			check: func(cfg *hydrateConfig) bool { return cfg.Bool },
+		},
+		{
+			name:  "flag from env",
+			cmd:   NewCommand("cmd", action).WithFlag(NewIntCmdInput("int").WithDefault(1).AsFlag().FromEnv([]string{"CLING_TEST_INT"})),
+			env:   map[string]string{"CLING_TEST_INT": "5"},
+			check: func(cfg *hydrateConfig) bool { return cfg.Int == 5 },
+		},
+		{
+			name:    "int flag validator",
+			cmd:     NewCommand("cmd", action).WithFlag(NewIntCmdInput("int").WithValidator(NewIntRangeValidator(1, 10)).Required().AsFlag()),
+			args:    []string{"--int", "11"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid default",
+			cmd:     NewCommand("cmd", action).WithFlag(NewIntCmdInput("int").WithDefault(0).WithValidator(NewIntRangeValidator(1, 10)).AsFlag()),
+			wantErr: true,
+		},
+		{
+			name:    "invalid env",
+			cmd:     NewCommand("cmd", action).WithFlag(NewIntCmdInput("int").WithDefault(1).WithValidator(NewIntRangeValidator(1, 10)).AsFlag().FromEnv([]string{"CLING_TEST_INT"})),
+			env:     map[string]string{"CLING_TEST_INT": "50"},
+			wantErr: true,
+		},
+		{
+			name: "slice flag repeated and comma separated",
+			cmd:  NewCommand("cmd", action).WithFlag(NewCmdSliceInput[int]("ints").WithDefault([]int{}).AsFlag()),
+			args: []string{"--ints", "1,2", "--ints", "3"},
+			check: func(cfg *hydrateConfig) bool {
+				return len(cfg.Ints) == 3 && cfg.Ints[0] == 1 && cfg.Ints[2] == 3
+			},
+		},
+		{
+			name:  "slice flag default",
+			cmd:   NewCommand("cmd", action).WithFlag(NewCmdSliceInput[float64]("floats").WithDefault([]float64{0.5, 1.5}).AsFlag()),
+			check: func(cfg *hydrateConfig) bool { return len(cfg.Floats) == 2 && cfg.Floats[1] == 1.5 },
+		},
+		{
+			name: "slice flag whole validator",
+			cmd: NewCommand("cmd", action).WithFlag(
+				NewCmdSliceInput[string]("strs").WithValidator(NewSliceUniqueValidator[string]()).WithDefault([]string{}).AsFlag(),
+			),
+			args:    []string{"--strs", "a,b,a"},
+			wantErr: true,
+		},
+		{
+			name: "slice flag element validator",
+			cmd: NewCommand("cmd", action).WithFlag(
+				NewCmdSliceInput[int]("ints").WithElementValidator(NewIntRangeValidator(1, 10)).WithDefault([]int{}).AsFlag(),
+			),
+			args:    []string{"--ints", "1,20"},
+			wantErr: true,
+		},
+		{
+			name: "slice flag element and whole validators",
+			cmd: NewCommand("cmd", action).WithFlag(
+				NewCmdSliceInput[int]("ints").
+					WithElementValidator(NewIntRangeValidator(1, 10)).
+					WithValidator(NewSliceMaxLengthValidator[int](3)).
+					WithDefault([]int{}).
+					AsFlag(),
+			),
+			args:  []string{"--ints", "1,2,3"},
+			check: func(cfg *hydrateConfig) bool { return len(cfg.Ints) == 3 },
+		},
+		{
+			name:  "string argument",
+			cmd:   NewCommand("cmd", action).WithArgument(NewStringCmdInput("arg").Required().AsArgument()),
+			args:  []string{"value"},
+			check: func(cfg *hydrateConfig) bool { return cfg.Arg == "value" },
+		},
+		{
+			name:    "string argument validator",
+			cmd:     NewCommand("cmd", action).WithArgument(NewStringCmdInput("arg").WithValidator(NewEnumValidator("a", "b")).Required().AsArgument()),
+			args:    []string{"c"},
+			wantErr: true,
+		},
+		{
+			name:    "int argument validator",
+			cmd:     NewCommand("cmd", action).WithArgument(NewIntCmdInput("intarg").WithValidator(NewIntRangeValidator(1, 10)).Required().AsArgument()),
+			args:    []string{"11"},
+			wantErr: true,
+		},
+		{
+			name: "optional argument default",
+			cmd: NewCommand("cmd", action).
+				WithArgument(NewStringCmdInput("arg").Required().AsArgument()).
+				WithArgument(NewIntCmdInput("intarg").WithDefault(3).AsArgument()),
+			args:  []string{"value"},
+			check: func(cfg *hydrateConfig) bool { return cfg.IntArg == 3 },
+		},
+		{
+			name:  "optional argument without default",
+			cmd:   NewCommand("cmd", action).WithArgument(NewStringCmdInput("arg").AsArgument()),
+			check: func(cfg *hydrateConfig) bool { return cfg.Arg == "" },
+		},
+		{
+			name:    "missing required argument",
+			cmd:     NewCommand("cmd", action).WithArgument(NewStringCmdInput("arg").Required().AsArgument()),
+			wantErr: true,
+		},
+		{
+			name: "trailing slice argument",
+			cmd: NewCommand("cmd", action).
+				WithArgument(NewStringCmdInput("arg").Required().AsArgument()).
+				WithArgument(NewCmdSliceInput[int]("rest").WithElementValidator(NewIntRangeValidator(0, 9)).AsArgument()),
+			args: []string{"value", "1", "2,3"},
+			check: func(cfg *hydrateConfig) bool {
+				return len(cfg.RestArg) == 3 && cfg.RestArg[2] == 3
+			},
+		},
+		{
+			name: "trailing slice argument element validator",
+			cmd: NewCommand("cmd", action).
+				WithArgument(NewCmdSliceInput[int]("rest").WithElementValidator(NewIntRangeValidator(0, 9)).AsArgument()),
+			args:    []string{"1", "10"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			cfg := &hydrateConfig{}
+			ctx := contextWithCommand(context.Background(), tt.cmd)
+			err := Hydrate(ctx, tt.args, cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error: %v, got: %v", tt.wantErr, err)
+			}
+			if err == nil && tt.check != nil && !tt.check(cfg) {
+				t.Fatalf("unexpected config: %+v", cfg)
+			}
+		})
+	}
+}
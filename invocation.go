@@ -0,0 +1,66 @@
+package cling
+
+import "context"
+
+// FlagValue is a flag given on the command line.
+type FlagValue struct {
+	// Name is the name of the flag without dashes. Built-in short flags like -h are expanded to their long name.
+	Name string
+	// Value is the value of the flag. It is empty for flags given without a value.
+	Value string
+}
+
+// Invocation describes how the running command was invoked.
+// Handlers and hooks get it from their context with InvocationFromContext.
+type Invocation struct {
+	// Command is the command that runs.
+	Command *Command
+	// Path lists the names of the commands from the top level command down to the one that runs.
+	// It is empty when the action of the CLI runs.
+	Path []string
+	// Args is the command line the CLI was run with, including the program name.
+	Args []string
+	// Flags lists the flags given on the command line in the order they were given.
+	// Flags the CLI handles itself, like --no-input, are left out.
+	Flags []FlagValue
+	// Positionals lists the positional arguments given to the command, after the command path.
+	Positionals []string
+}
+
+// IsSet reports whether the flag or argument with the given name was given on the command line,
+// as opposed to taking its value from the environment, a default or a prompt.
+func (i *Invocation) IsSet(name string) bool {
+	for _, flag := range i.Flags {
+		if flag.Name == name {
+			return true
+		}
+	}
+	for idx, arg := range i.Command.arguments {
+		if arg.Name() == name {
+			return idx < len(i.Positionals)
+		}
+	}
+	return false
+}
+
+// FlagValues returns the values the flag was given with on the command line, in order.
+func (i *Invocation) FlagValues(name string) []string {
+	values := []string{}
+	for _, flag := range i.Flags {
+		if flag.Name == name {
+			values = append(values, flag.Value)
+		}
+	}
+	return values
+}
+
+func contextWithInvocation(ctx context.Context, invocation *Invocation) context.Context {
+	return context.WithValue(ctx, ContextKeyInvocation, invocation)
+}
+
+// InvocationFromContext returns the invocation of the running command.
+// It returns false if the context is not derived from a CLIng supplied context.
+func InvocationFromContext(ctx context.Context) (*Invocation, bool) {
+	invocation, ok := ctx.Value(ContextKeyInvocation).(*Invocation)
+	return invocation, ok
+}
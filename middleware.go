@@ -0,0 +1,124 @@
+package cling
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var ErrPanic = errors.New("panic")
+
+// panicExitCode is the exit code of a command that panicked, the same a Go program exits with on a panic.
+const panicExitCode = 2
+
+// Middleware wraps the action of a command. It can run code around the action,
+// change its context or arguments, handle its error or skip it altogether.
+type Middleware func(next CommandHandler) CommandHandler
+
+// wrapAction wraps the action of the command with the middlewares of the CLI,
+// then with those of every command from the top level command down to this one.
+// The first middleware is the outermost.
+func (command *Command) wrapAction(middlewares []Middleware) CommandHandler {
+	chain := slices.Clone(middlewares)
+	path := command.pathToRoot()
+	for i := len(path) - 1; i >= 0; i-- {
+		chain = append(chain, path[i].middlewares...)
+	}
+	handler := command.action
+	for i := len(chain) - 1; i >= 0; i-- {
+		handler = chain[i](handler)
+	}
+	return handler
+}
+
+// PanicError is the error of an action that panicked. It is an ErrPanic and an ExitCoder with exit code 2.
+// If the panic value is an error, the PanicError wraps it as well.
+type PanicError struct {
+	value any
+	stack []byte
+}
+
+// Value returns the value the action panicked with.
+func (e *PanicError) Value() any {
+	return e.value
+}
+
+// Stack returns the stack of the goroutine at the time of the panic.
+func (e *PanicError) Stack() []byte {
+	return e.stack
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%v: %v", ErrPanic, e.value)
+}
+
+func (e *PanicError) ExitCode() int {
+	return panicExitCode
+}
+
+func (e *PanicError) Unwrap() []error {
+	if err, ok := e.value.(error); ok {
+		return []error{ErrPanic, err}
+	}
+	return []error{ErrPanic}
+}
+
+// NewRecoverMiddleware recovers from panics in the action and returns them as a *PanicError
+// that keeps the stack of the panic.
+func NewRecoverMiddleware() Middleware {
+	return func(next CommandHandler) CommandHandler {
+		return func(ctx context.Context, args []string) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = &PanicError{value: r, stack: debug.Stack()}
+				}
+			}()
+			return next(ctx, args)
+		}
+	}
+}
+
+// NewTimingMiddleware reports how long the action of the command ran, whether it failed, panicked or not.
+func NewTimingMiddleware(report func(command *Command, duration time.Duration)) Middleware {
+	return func(next CommandHandler) CommandHandler {
+		return func(ctx context.Context, args []string) error {
+			start := time.Now()
+			defer func() {
+				command, _ := commandFromContext(ctx)
+				report(command, time.Since(start))
+			}()
+			return next(ctx, args)
+		}
+	}
+}
+
+// NewLoggingMiddleware logs the start of the action at debug level and its end at info level,
+// or at error level if it failed, with the command path and the duration.
+func NewLoggingMiddleware(logger *slog.Logger) Middleware {
+	return func(next CommandHandler) CommandHandler {
+		return func(ctx context.Context, args []string) error {
+			path := ""
+			if command, ok := commandFromContext(ctx); ok {
+				path = strings.Join(command.commandPath(), " ")
+			}
+			logger.DebugContext(ctx, "running command", "command", path, "args", args)
+
+			start := time.Now()
+			err := next(ctx, args)
+			duration := time.Since(start)
+
+			if err != nil {
+				logger.ErrorContext(ctx, "command failed", "command", path, "duration", duration, "error", err)
+			} else {
+				logger.InfoContext(ctx, "command finished", "command", path, "duration", duration)
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package cling
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMiddlewareOrder(t *testing.T) {
+	calls := []string{}
+	trace := func(name string) Middleware {
+		return func(next CommandHandler) CommandHandler {
+			return func(ctx context.Context, args []string) error {
+				calls = append(calls, name+" before")
+				err := next(ctx, args)
+				calls = append(calls, name+" after")
+				return err
+			}
+		}
+	}
+	cli := NewCLI("test", "0.0.1").
+		WithIO(strings.NewReader(""), io.Discard, io.Discard).
+		Use(trace("cli")).
+		WithCommand(
+			NewCommand("parent", nil).
+				Use(trace("parent")).
+				WithChildCommand(
+					NewCommand("child", func(ctx context.Context, args []string) error {
+						calls = append(calls, "action")
+						return nil
+					}).Use(trace("child 1"), trace("child 2")),
+				),
+		)
+
+	if err := cli.Run(context.Background(), []string{"test", "parent", "child"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		"cli before", "parent before", "child 1 before", "child 2 before",
+		"action",
+		"child 2 after", "child 1 after", "parent after", "cli after",
+	}
+	if !slices.Equal(calls, want) {
+		t.Fatalf("expected calls %v, got %v", want, calls)
+	}
+}
+
+func TestBuiltInMiddlewares(t *testing.T) {
+	logs := bytes.NewBuffer(nil)
+	var timed *Command
+	var duration time.Duration
+	cli := NewCLI("test", "0.0.1").
+		WithIO(strings.NewReader(""), io.Discard, io.Discard).
+		Use(
+			NewLoggingMiddleware(slog.New(slog.NewTextHandler(logs, nil))),
+			NewRecoverMiddleware(),
+			NewTimingMiddleware(func(command *Command, d time.Duration) {
+				timed, duration = command, d
+			}),
+		).
+		WithCommand(NewCommand("boom", func(ctx context.Context, args []string) error {
+			panic("something broke")
+		}))
+
+	err := cli.Run(context.Background(), []string{"test", "boom"})
+	if !errors.Is(err, ErrPanic) || !strings.Contains(err.Error(), "something broke") {
+		t.Fatalf("expected a panic error, got %v", err)
+	}
+	var exitCoder ExitCoder
+	if !errors.As(err, &exitCoder) || exitCoder.ExitCode() != panicExitCode {
+		t.Fatalf("expected an exit coder with code %d, got %v", panicExitCode, err)
+	}
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) || !strings.Contains(string(panicErr.Stack()), "runtime/debug.Stack") {
+		t.Fatalf("expected the stack of the panic on the error, got %v", err)
+	}
+	if strings.Contains(err.Error(), "runtime/debug.Stack") {
+		t.Fatalf("expected no stack in the message of the error, got %v", err)
+	}
+	if timed == nil || timed.Name() != "boom" {
+		t.Fatalf("expected the timing of the panicking 'boom', got %v", timed)
+	}
+	if !strings.Contains(logs.String(), `level=ERROR msg="command failed" command=boom`) {
+		t.Fatalf("expected the failure to be logged, got %q", logs.String())
+	}
+
+	// a panic with an error keeps the error in the tree
+	panicValue := io.ErrUnexpectedEOF
+	cli.WithCommand(NewCommand("boom-error", func(ctx context.Context, args []string) error {
+		panic(panicValue)
+	}))
+	err = cli.Run(context.Background(), []string{"test", "boom-error"})
+	if !errors.Is(err, ErrPanic) || !errors.Is(err, panicValue) {
+		t.Fatalf("expected a panic error wrapping %v, got %v", panicValue, err)
+	}
+
+	cli.WithCommand(NewCommand("ok", NoOpHook))
+	if err := cli.Run(context.Background(), []string{"test", "ok"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if timed == nil || timed.Name() != "ok" || duration < 0 {
+		t.Fatalf("expected the timing of 'ok', got %v after %v", timed, duration)
+	}
+	if !strings.Contains(logs.String(), `level=INFO msg="command finished" command=ok`) {
+		t.Fatalf("expected the success to be logged, got %q", logs.String())
+	}
+}
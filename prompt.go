@@ -0,0 +1,111 @@
+package cling
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var ErrNoInput = errors.New("no input available")
+
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func newPrompter(in io.Reader, out io.Writer) *prompter {
+	return &prompter{
+		in:  bufio.NewReader(in),
+		out: out,
+	}
+}
+
+// promptInto prompts for the value of the input until it passes the pipeline and is set on the field.
+// An empty answer is only accepted if the input has a default, which it then takes.
+func (p *prompter) promptInto(ctx context.Context, input CmdInput, field reflect.Value, pipeline *valuePipeline) error {
+	for {
+		value, err := p.prompt(input)
+		if err != nil {
+			return err
+		}
+		if value == "" && !input.HasDefault() {
+			// an empty answer would read as true for bools
+			fmt.Fprintln(p.out, "A value is required")
+			continue
+		}
+		if err := pipeline.setField(ctx, field, []string{value}); err != nil {
+			fmt.Fprintf(p.out, "Invalid value: %v\n", err)
+			continue
+		}
+		return nil
+	}
+}
+
+// prompt asks for the value of the input.
+// Inputs with enum validators are presented as a select list.
+func (p *prompter) prompt(input CmdInput) (string, error) {
+	def := ""
+	if input.HasDefault() {
+		def = strings.Join(defaultValueStrings(input), ",")
+	}
+
+	label := input.Name()
+	if input.Description() != "" {
+		label = fmt.Sprintf("%s (%s)", label, input.Description())
+	}
+
+	choices := inputChoices(input)
+	if len(choices) > 0 {
+		fmt.Fprintf(p.out, "%s:\n", label)
+		for idx, choice := range choices {
+			fmt.Fprintf(p.out, "  %d) %s\n", idx+1, choice)
+		}
+		label = fmt.Sprintf("Select [1-%d]", len(choices))
+	}
+
+	if def != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", label, def)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", label)
+	}
+
+	answer, err := p.readLine()
+	if err != nil {
+		return "", err
+	}
+	if answer == "" {
+		return def, nil
+	}
+	// choices can be numbers themselves, so the answer is taken as an index only if it is not one of them
+	if slices.Contains(choices, answer) {
+		return answer, nil
+	}
+	if idx, err := strconv.Atoi(answer); err == nil && idx >= 1 && idx <= len(choices) {
+		return choices[idx-1], nil
+	}
+	return answer, nil
+}
+
+func (p *prompter) readLine() (string, error) {
+	line, err := p.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		fmt.Fprintln(p.out)
+		return "", errors.Wrap(ErrNoInput, err.Error())
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// inputChoices returns the allowed values of the input if it has an enum validator.
+func inputChoices(input CmdInput) []string {
+	if provider, ok := input.(ValidatorProvider); ok {
+		return validatorEnumValues(provider.getValidator())
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package cling
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+type terminalReader struct {
+	io.Reader
+}
+
+func (t *terminalReader) IsTerminal() bool {
+	return true
+}
+
+func TestInteractivePrompt(t *testing.T) {
+	type config struct {
+		Level string `cling-name:"level"`
+		Port  int    `cling-name:"port"`
+		Name  string `cling-name:"name"`
+	}
+
+	run := func(args []string, in io.Reader) (*config, string, error) {
+		cfg := &config{}
+		out := bytes.NewBuffer(nil)
+		cli := NewCLI("test", "0.0.1").
+			WithInteractive().
+			WithIO(in, io.Discard, out).
+			WithCommand(
+				NewCommand("cmd", func(ctx context.Context, args []string) error {
+					return Hydrate(ctx, args, cfg)
+				}).
+					WithFlag(NewStringCmdInput("level").WithValidator(NewEnumValidator("debug", "info")).Required().AsFlag()).
+					WithFlag(NewIntCmdInput("port").WithValidator(NewIntRangeValidator(1, 65535)).Required().AsFlag()).
+					WithArgument(NewStringCmdInput("name").WithDescription("Name of the thing").Required().AsArgument()),
+			)
+		err := cli.Run(context.Background(), args)
+		return cfg, out.String(), err
+	}
+
+	cfg, out, err := run([]string{"test", "cmd"}, &terminalReader{strings.NewReader("2\n0\n8080\nthing\n")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Level != "info" || cfg.Port != 8080 || cfg.Name != "thing" {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+	if !strings.Contains(out, "1) debug") || !strings.Contains(out, "Invalid value") || !strings.Contains(out, "Name of the thing") {
+		t.Fatalf("unexpected prompt output: %s", out)
+	}
+
+	if _, _, err := run([]string{"test", "cmd", "--no-input"}, &terminalReader{strings.NewReader("2\n8080\nthing\n")}); err == nil {
+		t.Fatalf("expected error with --no-input")
+	}
+
+	if _, _, err := run([]string{"test", "cmd"}, strings.NewReader("2\n8080\nthing\n")); err == nil {
+		t.Fatalf("expected error when stdin is not a terminal")
+	}
+
+	if _, _, err := run([]string{"test", "cmd"}, &terminalReader{strings.NewReader("2\n")}); err == nil {
+		t.Fatalf("expected error when input runs out")
+	}
+}
+
+func TestPromptEmptyAnswer(t *testing.T) {
+	type config struct {
+		Force bool `cling-name:"force"`
+	}
+	run := func(in string) (*config, string, error) {
+		cfg := &config{Force: true}
+		out := bytes.NewBuffer(nil)
+		cli := NewCLI("test", "0.0.1").
+			WithInteractive().
+			WithIO(&terminalReader{strings.NewReader(in)}, io.Discard, out).
+			WithCommand(
+				NewCommand("cmd", func(ctx context.Context, args []string) error {
+					return Hydrate(ctx, args, cfg)
+				}).WithFlag(NewBoolCmdInput("force").Required().AsFlag()),
+			)
+		err := cli.Run(context.Background(), []string{"test", "cmd"})
+		return cfg, out.String(), err
+	}
+
+	cfg, out, err := run("\nfalse\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Force || !strings.Contains(out, "A value is required") {
+		t.Fatalf("expected the empty answer to be asked again, got %v with output %q", cfg.Force, out)
+	}
+
+	if _, _, err := run("\n"); !errors.Is(err, ErrNoInput) {
+		t.Fatalf("expected ErrNoInput after an empty answer, got %v", err)
+	}
+}
+
+func TestPromptNumericEnum(t *testing.T) {
+	tests := []struct {
+		name    string
+		choices []int
+		answer  string
+		want    int
+	}{
+		{"value out of index range", []int{10, 20, 30}, "20", 20},
+		{"index", []int{10, 20, 30}, "2", 20},
+		{"value that is also an index", []int{3, 2, 1}, "1", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			type config struct {
+				Replicas int `cling-name:"replicas"`
+			}
+			cfg := &config{}
+			cli := NewCLI("test", "0.0.1").
+				WithInteractive().
+				WithIO(&terminalReader{strings.NewReader(tt.answer + "\n")}, io.Discard, io.Discard).
+				WithCommand(
+					NewCommand("scale", func(ctx context.Context, args []string) error {
+						return Hydrate(ctx, args, cfg)
+					}).WithFlag(NewIntCmdInput("replicas").WithValidator(NewEnumValidator(tt.choices...)).Required().AsFlag()),
+				)
+			if err := cli.Run(context.Background(), []string{"test", "scale"}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cfg.Replicas != tt.want {
+				t.Fatalf("expected %d, got %d", tt.want, cfg.Replicas)
+			}
+		})
+	}
+}
+
+func TestConfirmation(t *testing.T) {
+	run := func(args []string, in io.Reader) (bool, string, error) {
+		ran := false
+		cfg := &struct {
+			Target string `cling-name:"target"`
+		}{}
+		cli := NewCLI("test", "0.0.1").
+			WithIO(in, io.Discard, io.Discard).
+			WithCommand(
+				NewCommand("delete", func(ctx context.Context, args []string) error {
+					ran = true
+					return Hydrate(ctx, args, cfg)
+				}).
+					WithArgument(NewStringCmdInput("target").WithDefault("").AsArgument()).
+					WithConfirmation("Delete everything?"),
+			)
+		err := cli.Run(context.Background(), args)
+		return ran, cfg.Target, err
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		in         io.Reader
+		wantRan    bool
+		wantTarget string
+	}{
+		{"confirmed", []string{"test", "delete"}, &terminalReader{strings.NewReader("y\n")}, true, ""},
+		{"declined", []string{"test", "delete"}, &terminalReader{strings.NewReader("n\n")}, false, ""},
+		{"empty answer", []string{"test", "delete"}, &terminalReader{strings.NewReader("\n")}, false, ""},
+		{"yes flag", []string{"test", "delete", "--yes"}, strings.NewReader(""), true, ""},
+		{"short yes flag", []string{"test", "delete", "-y"}, strings.NewReader(""), true, ""},
+		{"yes flag before positional", []string{"test", "delete", "--yes", "x"}, strings.NewReader(""), true, "x"},
+		{"short yes flag before positional", []string{"test", "delete", "-y", "x"}, strings.NewReader(""), true, "x"},
+		{"yes flag false", []string{"test", "delete", "--yes=false", "x"}, strings.NewReader(""), false, ""},
+		{"no input before positional", []string{"test", "delete", "--no-input", "x", "--yes"}, strings.NewReader(""), true, "x"},
+		{"no color before positional", []string{"test", "delete", "--no-color", "x", "--yes"}, strings.NewReader(""), true, "x"},
+		{"dash positional", []string{"test", "delete", "--yes", "-x"}, strings.NewReader(""), true, "-x"},
+		{"not a terminal", []string{"test", "delete"}, strings.NewReader("y\n"), false, ""},
+		{"no input", []string{"test", "delete", "--no-input"}, &terminalReader{strings.NewReader("y\n")}, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ran, target, err := run(tt.args, tt.in)
+			if ran != tt.wantRan {
+				t.Fatalf("expected action to run: %v, got: %v (err: %v)", tt.wantRan, ran, err)
+			}
+			if !ran && !errors.Is(err, ErrNotConfirmed) {
+				t.Fatalf("expected ErrNotConfirmed, got: %v", err)
+			}
+			if ran && (err != nil || target != tt.wantTarget) {
+				t.Fatalf("expected target %q, got %q (err: %v)", tt.wantTarget, target, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package cling
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// schemaFlag dumps the schema of the CLI as JSON. It is not listed in the help.
+const schemaFlag = "cling-schema"
+
+// Schema is a serializable description of a CLI and all of its commands,
+// for tools that introspect the CLI without parsing its help.
+type Schema struct {
+	Name            string `json:"name"`
+	Version         string `json:"version"`
+	Description     string `json:"description,omitempty"`
+	LongDescription string `json:"longDescription,omitempty"`
+	// Runnable reports whether the CLI has an action that runs when no command is given.
+	Runnable       bool            `json:"runnable"`
+	DefaultCommand string          `json:"defaultCommand,omitempty"`
+	Flags          []InputSchema   `json:"flags"`
+	Arguments      []InputSchema   `json:"arguments"`
+	Commands       []CommandSchema `json:"commands"`
+}
+
+// CommandSchema is a serializable description of a command and its children.
+type CommandSchema struct {
+	Name string `json:"name"`
+	// Path lists the names of the commands from the top level command down to this one.
+	Path            []string `json:"path"`
+	Aliases         []string `json:"aliases,omitempty"`
+	Description     string   `json:"description,omitempty"`
+	LongDescription string   `json:"longDescription,omitempty"`
+	Group           string   `json:"group,omitempty"`
+	Hidden          bool     `json:"hidden,omitempty"`
+	// Runnable reports whether the command has an action, rather than only grouping its children.
+	Runnable     bool            `json:"runnable"`
+	Confirmation string          `json:"confirmation,omitempty"`
+	Flags        []InputSchema   `json:"flags"`
+	Arguments    []InputSchema   `json:"arguments"`
+	Examples     []ExampleSchema `json:"examples,omitempty"`
+	SeeAlso      []string        `json:"seeAlso,omitempty"`
+	Commands     []CommandSchema `json:"commands"`
+}
+
+// ExampleSchema is a serializable description of an example of a command.
+type ExampleSchema struct {
+	Description string `json:"description,omitempty"`
+	CommandLine string `json:"commandLine"`
+}
+
+// InputSchema is a serializable description of a flag or an argument.
+type InputSchema struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Type is the Go type of the value, like 'string', 'int', 'bool' or '[]string'.
+	Type     string `json:"type"`
+	Required bool   `json:"required"`
+	// Default is the default value, if the input has one.
+	Default any `json:"default,omitempty"`
+	// Constraint describes the values the validators of the input accept.
+	Constraint string `json:"constraint,omitempty"`
+	// EnumValues lists the values the input accepts, if its validator accepts a fixed set of values.
+	EnumValues []string `json:"enumValues,omitempty"`
+	// EnvVars lists the environment variables a flag is read from.
+	EnvVars []string `json:"envVars,omitempty"`
+	Group   string   `json:"group,omitempty"`
+}
+
+// Schema returns a serializable description of the CLI and all of its commands, including hidden ones.
+// The same description is printed as JSON when the CLI is run with --cling-schema.
+func (c *CLI) Schema() *Schema {
+	schema := &Schema{
+		Name:            c.name,
+		Version:         c.version,
+		Description:     c.description,
+		LongDescription: c.longDescription,
+		Runnable:        c.root.action != nil,
+		DefaultCommand:  c.defaultCommand,
+		Flags:           flagsSchema(c.root.flags),
+		Arguments:       argsSchema(c.root.arguments),
+		Commands:        commandsSchema(c.commands),
+	}
+	return schema
+}
+
+// printSchema prints the schema of the CLI as indented JSON.
+func (c *CLI) printSchema() error {
+	schema, err := json.MarshalIndent(c.Schema(), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(c.stdout, string(schema))
+	return err
+}
+
+func commandsSchema(commands []*Command) []CommandSchema {
+	schemas := make([]CommandSchema, 0, len(commands))
+	for _, cmd := range commands {
+		schema := CommandSchema{
+			Name:            cmd.name,
+			Path:            cmd.commandPath(),
+			Aliases:         cmd.aliases,
+			Description:     cmd.description,
+			LongDescription: cmd.longDescription,
+			Group:           cmd.group,
+			Hidden:          cmd.hidden,
+			Runnable:        cmd.action != nil,
+			Confirmation:    cmd.confirmation,
+			Flags:           flagsSchema(cmd.flags),
+			Arguments:       argsSchema(cmd.arguments),
+			SeeAlso:         cmd.seeAlso,
+			Commands:        commandsSchema(cmd.children),
+		}
+		for _, ex := range cmd.examples {
+			schema.Examples = append(schema.Examples, ExampleSchema{Description: ex.description, CommandLine: ex.commandLine})
+		}
+		schemas = append(schemas, schema)
+	}
+	return schemas
+}
+
+func flagsSchema(flags []CmdFlag) []InputSchema {
+	schemas := make([]InputSchema, 0, len(flags))
+	for _, flag := range flags {
+		schema := inputSchema(flag)
+		schema.EnvVars = flag.EnvSources()
+		schema.Group = flag.Group()
+		schemas = append(schemas, schema)
+	}
+	return schemas
+}
+
+func argsSchema(args []CmdArg) []InputSchema {
+	schemas := make([]InputSchema, 0, len(args))
+	for _, arg := range args {
+		schemas = append(schemas, inputSchema(arg))
+	}
+	return schemas
+}
+
+func inputSchema(input CmdInput) InputSchema {
+	schema := InputSchema{
+		Name:        input.Name(),
+		Description: input.Description(),
+		Type:        input.ValueType().String(),
+		Required:    input.IsRequired(),
+		Constraint:  inputConstraint(input),
+		EnumValues:  inputChoices(input),
+	}
+	if provider, ok := input.(elementValidatorProvider); ok && schema.EnumValues == nil {
+		// the values every element of a slice accepts
+		schema.EnumValues = validatorEnumValues(provider.getElementValidator())
+	}
+	if input.HasDefault() {
+		schema.Default = input.Default()
+	}
+	return schema
+}
@@ -0,0 +1,107 @@
+package cling
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSchema(t *testing.T) {
+	stdout := bytes.NewBuffer(nil)
+	cli := NewCLI("test", "0.0.1").
+		WithIO(strings.NewReader(""), stdout, io.Discard).
+		WithDescription("Test CLI").
+		WithCommand(
+			NewCommand("cluster", nil).
+				WithAliases("c").
+				WithChildCommand(
+					NewCommand("create", NoOpHook).
+						WithGroup("Lifecycle").
+						WithExample("Create a large cluster", "test cluster create prod --size large").
+						WithArgument(NewStringCmdInput("name").Required().AsArgument()).
+						WithFlag(NewStringCmdInput("size").
+							WithDefault("small").
+							WithValidator(NewEnumValidator("small", "large")).
+							AsFlag().
+							FromEnv([]string{"CLUSTER_SIZE"})).
+						WithFlag(NewCmdSliceInput[string]("zone").
+							WithElementValidator(NewEnumValidator("a", "b")).
+							WithDefault([]string{"a"}).
+							AsFlag()),
+				),
+		).
+		WithCommand(NewCommand("debug", NoOpHook).Hidden())
+
+	if err := cli.Run(context.Background(), []string{"test", "--cling-schema"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	schema := &Schema{}
+	if err := json.Unmarshal(stdout.Bytes(), schema); err != nil {
+		t.Fatalf("expected the schema as JSON, got %q: %v", stdout.String(), err)
+	}
+
+	if schema.Name != "test" || schema.Version != "0.0.1" || schema.Description != "Test CLI" || schema.Runnable {
+		t.Fatalf("unexpected CLI schema: %+v", schema)
+	}
+	if len(schema.Commands) != 2 || !schema.Commands[1].Hidden {
+		t.Fatalf("expected the hidden command in the schema, got %+v", schema.Commands)
+	}
+
+	cluster := schema.Commands[0]
+	if cluster.Runnable || !reflect.DeepEqual(cluster.Aliases, []string{"c"}) || len(cluster.Commands) != 1 {
+		t.Fatalf("unexpected cluster schema: %+v", cluster)
+	}
+
+	create := cluster.Commands[0]
+	if !reflect.DeepEqual(create.Path, []string{"cluster", "create"}) || !create.Runnable || create.Group != "Lifecycle" {
+		t.Fatalf("unexpected create schema: %+v", create)
+	}
+	wantArgs := []InputSchema{{Name: "name", Type: "string", Required: true}}
+	if !reflect.DeepEqual(create.Arguments, wantArgs) {
+		t.Fatalf("expected arguments %+v, got %+v", wantArgs, create.Arguments)
+	}
+	wantFlags := []InputSchema{
+		{
+			Name:       "size",
+			Type:       "string",
+			Default:    "small",
+			Constraint: "one of small|large",
+			EnumValues: []string{"small", "large"},
+			EnvVars:    []string{"CLUSTER_SIZE"},
+		},
+		{
+			Name:       "zone",
+			Type:       "[]string",
+			Default:    []any{"a"},
+			Constraint: "each one of a|b",
+			EnumValues: []string{"a", "b"},
+		},
+	}
+	if !reflect.DeepEqual(create.Flags, wantFlags) {
+		t.Fatalf("expected flags %+v, got %+v", wantFlags, create.Flags)
+	}
+
+	// every key of the schema is camelCase
+	for _, key := range []string{
+		`"name": "create"`,
+		`"path": [`,
+		`"runnable": true`,
+		`"group": "Lifecycle"`,
+		`"examples": [`,
+		`"description": "Create a large cluster"`,
+		`"commandLine": "test cluster create prod --size large"`,
+		`"enumValues": [`,
+		`"envVars": [`,
+	} {
+		if !strings.Contains(stdout.String(), key) {
+			t.Fatalf("expected %s in the schema, got %s", key, stdout.String())
+		}
+	}
+	if strings.Contains(stdout.String(), `"CommandLine"`) || strings.Contains(stdout.String(), `"Description"`) {
+		t.Fatalf("expected no capitalized keys in the schema, got %s", stdout.String())
+	}
+}
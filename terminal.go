@@ -0,0 +1,35 @@
+package cling
+
+import "os"
+
+// Terminal is implemented by readers and writers that know whether they are attached to an interactive terminal.
+// Readers supplied through WithIO can implement it to enable interactive behavior in tests.
+type Terminal interface {
+	IsTerminal() bool
+}
+
+// isTerminal reports whether the given reader or writer is attached to an interactive terminal.
+func isTerminal(stream any) bool {
+	switch s := stream.(type) {
+	case Terminal:
+		return s.IsTerminal()
+	case *os.File:
+		info, err := s.Stat()
+		if err != nil {
+			return false
+		}
+		return info.Mode()&os.ModeCharDevice != 0
+	default:
+		return false
+	}
+}
+
+// terminalWidth returns the number of columns of the terminal the writer is attached to,
+// or 0 when it is not a terminal or its size is unknown.
+func terminalWidth(stream any) int {
+	f, ok := stream.(*os.File)
+	if !ok || !isTerminal(f) {
+		return 0
+	}
+	return fileTerminalWidth(f)
+}
@@ -0,0 +1,10 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package cling
+
+import "os"
+
+// fileTerminalWidth is not supported on this platform - the width falls back to COLUMNS.
+func fileTerminalWidth(f *os.File) int {
+	return 0
+}
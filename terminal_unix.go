@@ -0,0 +1,21 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package cling
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// fileTerminalWidth asks the terminal behind the file for its size.
+func fileTerminalWidth(f *os.File) int {
+	var size struct {
+		rows, cols, xpixels, ypixels uint16
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&size)))
+	if errno != 0 {
+		return 0
+	}
+	return int(size.cols)
+}
@@ -0,0 +1,93 @@
+package cling
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Transformer transforms a parsed value before it is validated.
+// The context is the context of the running command, so that transformers can read the environment of the CLI.
+type Transformer[T any] func(ctx context.Context, value T) (T, error)
+
+type transformerAny interface {
+	Transform(ctx context.Context, value any) (any, error)
+}
+
+type genericTransformerWrapper[S any] struct {
+	transforms []Transformer[S]
+}
+
+func (g *genericTransformerWrapper[S]) Transform(ctx context.Context, value any) (any, error) {
+	val, ok := convertTo[S](value)
+	if !ok {
+		return nil, fmt.Errorf("invalid type: expected %T, got %T", *new(S), value)
+	}
+	for _, transform := range g.transforms {
+		var err error
+		if val, err = transform(ctx, val); err != nil {
+			return nil, err
+		}
+	}
+	return val, nil
+}
+
+type noOpTransformer struct{}
+
+func (t *noOpTransformer) Transform(ctx context.Context, value any) (any, error) {
+	return value, nil
+}
+
+// NewLowercaseTransformer creates a new transformer that lowercases the value.
+func NewLowercaseTransformer() Transformer[string] {
+	return func(ctx context.Context, value string) (string, error) {
+		return strings.ToLower(value), nil
+	}
+}
+
+// NewTrimSpaceTransformer creates a new transformer that trims leading and trailing whitespace from the value.
+func NewTrimSpaceTransformer() Transformer[string] {
+	return func(ctx context.Context, value string) (string, error) {
+		return strings.TrimSpace(value), nil
+	}
+}
+
+// NewExpandPathTransformer creates a new transformer that expands a leading '~' to the home directory,
+// expands environment variables and converts the value to an absolute path.
+// Variables are read with the environment lookup of the CLI, like flags with env sources.
+func NewExpandPathTransformer() Transformer[string] {
+	return func(ctx context.Context, value string) (string, error) {
+		if value == "" {
+			return value, nil
+		}
+		lookupEnv := envLookupFromContext(ctx)
+		value = os.Expand(value, func(key string) string {
+			val, _ := lookupEnv(key)
+			return val
+		})
+		if value == "~" || strings.HasPrefix(value, "~/") {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", err
+			}
+			value = filepath.Join(home, strings.TrimPrefix(value, "~"))
+		}
+		return filepath.Abs(value)
+	}
+}
+
+// NewEnumCanonicalTransformer creates a new transformer that replaces a case-insensitive match
+// of one of the allowed values with the allowed value itself.
+// Values that do not match are returned unchanged.
+func NewEnumCanonicalTransformer(allowedValues ...string) Transformer[string] {
+	return func(ctx context.Context, value string) (string, error) {
+		for _, allowed := range allowedValues {
+			if strings.EqualFold(value, allowed) {
+				return allowed, nil
+			}
+		}
+		return value, nil
+	}
+}
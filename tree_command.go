@@ -0,0 +1,51 @@
+package cling
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const treeCommand = "tree"
+
+// runTree runs the built-in tree command, printing the commands below the given command path.
+func (c *CLI) runTree(names []string, format helpFormat) error {
+	title := c.name
+	commands := c.commands
+	if len(names) > 0 {
+		command := c.findCommand(names)
+		if command == nil || len(command.pathToRoot()) != len(names) {
+			return errors.Wrapf(ErrUnknownCommand, "no command '%s'", strings.Join(names, " "))
+		}
+		title = strings.Join(append([]string{c.name}, command.commandPath()...), " ")
+		commands = command.children
+	}
+
+	fmt.Fprintln(c.stdout, title)
+	writeTable(c.stdout, treeRows(commands, "", c.helpOrder), format.width)
+	return nil
+}
+
+// treeRows returns a row of the name, drawn as a branch of the tree, and the description of every command, depth first.
+func treeRows(commands []*Command, prefix string, order HelpOrder) [][]string {
+	if order == HelpOrderAlphabetical {
+		commands = slices.Clone(commands)
+		slices.SortStableFunc(commands, func(a, b *Command) int {
+			return strings.Compare(a.name, b.name)
+		})
+	}
+
+	rows := [][]string{}
+	commands = visibleCommands(commands)
+	for idx, cmd := range commands {
+		branch, indent := "├── ", "│   "
+		if idx == len(commands)-1 {
+			branch, indent = "└── ", "    "
+		}
+		rows = append(rows, []string{prefix + branch + cmd.name, cmd.description})
+		rows = append(rows, treeRows(cmd.children, prefix+indent, order)...)
+	}
+	return rows
+}
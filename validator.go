@@ -1,8 +1,11 @@
 package cling
 
 import (
-	"errors"
+	stdErrs "errors"
 	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
 )
 
 var ErrValidatorFailed = errors.New("validation failed")
@@ -12,22 +15,68 @@ type Validator[T any] interface {
 	Validate(value T) error
 }
 
+// Describer is implemented by validators that can describe the constraint they enforce.
+// The description is shown in help output.
+type Describer interface {
+	Describe() string
+}
+
+// enumerator is implemented by validators that only accept a fixed set of values.
+type enumerator interface {
+	enumValues() []string
+}
+
+// validatorEnumValues returns the values accepted by the validator, or nil if it is not an enumerator.
+func validatorEnumValues(validator any) []string {
+	if e, ok := validator.(enumerator); ok {
+		return e.enumValues()
+	}
+	return nil
+}
+
 type validatorAny interface {
 	Validate(value any) error
 }
 
+// describeValidator returns the description of the validator, or an empty string if it has none.
+func describeValidator(validator any) string {
+	if describer, ok := validator.(Describer); ok {
+		return describer.Describe()
+	}
+	return ""
+}
+
+// describeValidators returns the non-empty descriptions of the validators joined by the separator.
+func describeValidators[T any](validators []Validator[T], separator string) string {
+	descriptions := make([]string, 0, len(validators))
+	for _, validator := range validators {
+		if description := describeValidator(validator); description != "" {
+			descriptions = append(descriptions, description)
+		}
+	}
+	return strings.Join(descriptions, separator)
+}
+
 type genericValidatorWrapper[S any] struct {
 	validator Validator[S]
 }
 
 func (g *genericValidatorWrapper[S]) Validate(value any) error {
-	val, ok := value.(S)
+	val, ok := convertTo[S](value)
 	if !ok {
-		return fmt.Errorf("invalid type: expected %T, got %T", new(S), value)
+		return fmt.Errorf("invalid type: expected %T, got %T", *new(S), value)
 	}
 	return g.validator.Validate(val)
 }
 
+func (g *genericValidatorWrapper[S]) Describe() string {
+	return describeValidator(g.validator)
+}
+
+func (g *genericValidatorWrapper[S]) enumValues() []string {
+	return validatorEnumValues(g.validator)
+}
+
 // NoOpValidator returns a no-op validator for any type
 func NoOpValidator() validatorAny {
 	return &noOpValidator{}
@@ -48,6 +97,19 @@ type compositeValidator[T any] struct {
 	validators []Validator[T]
 }
 
+func (v *compositeValidator[T]) Describe() string {
+	return describeValidators(v.validators, ", ")
+}
+
+func (v *compositeValidator[T]) enumValues() []string {
+	for _, validator := range v.validators {
+		if values := validatorEnumValues(validator); values != nil {
+			return values
+		}
+	}
+	return nil
+}
+
 func (v *compositeValidator[T]) Validate(value T) error {
 	for _, validator := range v.validators {
 		if err := validator.Validate(value); err != nil {
@@ -56,3 +118,57 @@ func (v *compositeValidator[T]) Validate(value T) error {
 	}
 	return nil
 }
+
+// Not negates a validator for a specific type.
+// The resulting validator fails if the given validator succeeds.
+func Not[T any](validator Validator[T]) Validator[T] {
+	return &notValidator[T]{validator: validator}
+}
+
+type notValidator[T any] struct {
+	validator Validator[T]
+}
+
+func (v *notValidator[T]) Describe() string {
+	if description := describeValidator(v.validator); description != "" {
+		return fmt.Sprintf("not %s", description)
+	}
+	return ""
+}
+
+func (v *notValidator[T]) Validate(value T) error {
+	if err := v.validator.Validate(value); err != nil {
+		return nil
+	}
+	return errors.Wrapf(ErrValidatorFailed, "value '%v' is not allowed", value)
+}
+
+// AnyOf composes multiple validators for a specific type.
+// The resulting validator succeeds if at least one of the validators succeeds.
+// Without validators it rejects every value.
+func AnyOf[T any](validators ...Validator[T]) Validator[T] {
+	return &anyOfValidator[T]{validators: validators}
+}
+
+type anyOfValidator[T any] struct {
+	validators []Validator[T]
+}
+
+func (v *anyOfValidator[T]) Describe() string {
+	return describeValidators(v.validators, " or ")
+}
+
+func (v *anyOfValidator[T]) Validate(value T) error {
+	if len(v.validators) == 0 {
+		return errors.Wrapf(ErrValidatorFailed, "value '%v' is not allowed", value)
+	}
+	errs := make([]error, 0, len(v.validators))
+	for _, validator := range v.validators {
+		err := validator.Validate(value)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+	}
+	return stdErrs.Join(errs...)
+}
@@ -1,6 +1,11 @@
 package cling
 
-import "github.com/pkg/errors"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
 
 type Comparator[T any] func(a T) error
 
@@ -19,14 +24,34 @@ func (v *comparatorValidator[T]) Validate(value T) error {
 	return v.comparator(value)
 }
 
+type enumValidator[T comparable] struct {
+	allowedValues []T
+}
+
 // NewEnumValidator creates a new validator that checks if the value is one of the allowed values.
 func NewEnumValidator[T comparable](allowedValues ...T) Validator[T] {
-	return NewComparatorValidator[T](func(value T) error {
-		for _, allowed := range allowedValues {
-			if value == allowed {
-				return nil
-			}
+	return &enumValidator[T]{
+		allowedValues: allowedValues,
+	}
+}
+
+func (v *enumValidator[T]) Validate(value T) error {
+	for _, allowed := range v.allowedValues {
+		if value == allowed {
+			return nil
 		}
-		return errors.Wrapf(ErrValidatorFailed, "value '%v' is not in the allowed enum values", value)
-	})
+	}
+	return errors.Wrapf(ErrValidatorFailed, "value '%v' is not in the allowed enum values", value)
+}
+
+func (v *enumValidator[T]) Describe() string {
+	return fmt.Sprintf("one of %s", strings.Join(v.enumValues(), "|"))
+}
+
+func (v *enumValidator[T]) enumValues() []string {
+	values := make([]string, len(v.allowedValues))
+	for i, allowed := range v.allowedValues {
+		values[i] = fmt.Sprint(allowed)
+	}
+	return values
 }
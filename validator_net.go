@@ -0,0 +1,74 @@
+package cling
+
+import (
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// NewURLValidator creates a new validator that checks if the value is an absolute URL.
+// If allowedSchemes are given, the scheme of the URL must be one of them.
+func NewURLValidator(allowedSchemes ...string) Validator[string] {
+	// url.Parse lowercases the scheme
+	schemes := make([]string, 0, len(allowedSchemes))
+	for _, scheme := range allowedSchemes {
+		schemes = append(schemes, strings.ToLower(scheme))
+	}
+	return &stringValidator{
+		fn: func(value string) error {
+			u, err := url.Parse(value)
+			if err != nil || u.Scheme == "" || u.Host == "" {
+				return errors.Wrapf(ErrValidatorFailed, "value '%s' is not a valid URL", value)
+			}
+			if len(allowedSchemes) > 0 && !slices.Contains(schemes, strings.ToLower(u.Scheme)) {
+				return errors.Wrapf(ErrValidatorFailed, "URL scheme '%s' is not one of %v", u.Scheme, allowedSchemes)
+			}
+			return nil
+		},
+		description: describeURL(allowedSchemes),
+	}
+}
+
+// NewEmailValidator creates a new validator that checks if the value is a bare email address.
+func NewEmailValidator() Validator[string] {
+	return &stringValidator{
+		fn: func(value string) error {
+			addr, err := mail.ParseAddress(value)
+			if err != nil || addr.Address != value {
+				return errors.Wrapf(ErrValidatorFailed, "value '%s' is not a valid email address", value)
+			}
+			return nil
+		},
+		description: "email address",
+	}
+}
+
+// NewHostPortValidator creates a new validator that checks if the value is of the form host:port.
+func NewHostPortValidator() Validator[string] {
+	return &stringValidator{
+		fn: func(value string) error {
+			_, port, err := net.SplitHostPort(value)
+			if err != nil {
+				return errors.Wrapf(ErrValidatorFailed, "value '%s' is not a valid host:port", value)
+			}
+			if p, err := strconv.Atoi(port); err != nil || p < 0 || p > 65535 {
+				return errors.Wrapf(ErrValidatorFailed, "port '%s' is not in range [0, 65535]", port)
+			}
+			return nil
+		},
+		description: "host:port",
+	}
+}
+
+func describeURL(allowedSchemes []string) string {
+	if len(allowedSchemes) == 0 {
+		return "URL"
+	}
+	return fmt.Sprintf("%s URL", strings.Join(allowedSchemes, "|"))
+}
@@ -0,0 +1,95 @@
+package cling
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// NewPathExistsValidator creates a new validator that checks if the value is an existing path.
+func NewPathExistsValidator() Validator[string] {
+	return &stringValidator{
+		fn: func(value string) error {
+			if _, err := os.Stat(value); err != nil {
+				return errors.Wrapf(ErrValidatorFailed, "path '%s' does not exist", value)
+			}
+			return nil
+		},
+		description: "existing path",
+	}
+}
+
+// NewFileExistsValidator creates a new validator that checks if the value is an existing regular file.
+func NewFileExistsValidator() Validator[string] {
+	return &stringValidator{
+		fn: func(value string) error {
+			info, err := os.Stat(value)
+			if err != nil {
+				return errors.Wrapf(ErrValidatorFailed, "file '%s' does not exist", value)
+			}
+			if !info.Mode().IsRegular() {
+				return errors.Wrapf(ErrValidatorFailed, "path '%s' is not a regular file", value)
+			}
+			return nil
+		},
+		description: "existing file",
+	}
+}
+
+// NewDirExistsValidator creates a new validator that checks if the value is an existing directory.
+func NewDirExistsValidator() Validator[string] {
+	return &stringValidator{
+		fn: func(value string) error {
+			info, err := os.Stat(value)
+			if err != nil {
+				return errors.Wrapf(ErrValidatorFailed, "directory '%s' does not exist", value)
+			}
+			if !info.IsDir() {
+				return errors.Wrapf(ErrValidatorFailed, "path '%s' is not a directory", value)
+			}
+			return nil
+		},
+		description: "existing directory",
+	}
+}
+
+// NewPathWritableValidator creates a new validator that checks if the value is a writable path.
+// If the path does not exist, its parent directory must be writable.
+func NewPathWritableValidator() Validator[string] {
+	return &stringValidator{
+		fn: func(value string) error {
+			info, err := os.Stat(value)
+			switch {
+			case os.IsNotExist(err):
+				if !isDirWritable(filepath.Dir(value)) {
+					return errors.Wrapf(ErrValidatorFailed, "path '%s' cannot be created", value)
+				}
+			case err != nil:
+				return errors.Wrapf(ErrValidatorFailed, "path '%s' cannot be accessed", value)
+			case info.IsDir():
+				if !isDirWritable(value) {
+					return errors.Wrapf(ErrValidatorFailed, "directory '%s' is not writable", value)
+				}
+			default:
+				f, err := os.OpenFile(value, os.O_WRONLY, 0)
+				if err != nil {
+					return errors.Wrapf(ErrValidatorFailed, "file '%s' is not writable", value)
+				}
+				_ = f.Close()
+			}
+			return nil
+		},
+		description: "writable path",
+	}
+}
+
+func isDirWritable(dir string) bool {
+	f, err := os.CreateTemp(dir, ".cling-writable-*")
+	if err != nil {
+		return false
+	}
+	_ = f.Close()
+	_ = os.Remove(f.Name())
+	return true
+}
@@ -0,0 +1,32 @@
+package cling
+
+import (
+	"cmp"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+type rangeValidator[T cmp.Ordered] struct {
+	min T
+	max T
+}
+
+// NewRangeValidator creates a new validator that checks if the value is within [min, max].
+func NewRangeValidator[T cmp.Ordered](min, max T) Validator[T] {
+	return &rangeValidator[T]{
+		min: min,
+		max: max,
+	}
+}
+
+func (v *rangeValidator[T]) Describe() string {
+	return fmt.Sprintf("between %v and %v", v.min, v.max)
+}
+
+func (v *rangeValidator[T]) Validate(value T) error {
+	if cmp.Less(value, v.min) || cmp.Less(v.max, value) {
+		return errors.Wrapf(ErrValidatorFailed, "value %v is not in range [%v, %v]", value, v.min, v.max)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package cling
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+type sliceValidator[T any] struct {
+	fn          func(value []T) error
+	description string
+}
+
+func (v *sliceValidator[T]) Validate(value []T) error {
+	return v.fn(value)
+}
+
+func (v *sliceValidator[T]) Describe() string {
+	return v.description
+}
+
+// NewSliceMinLengthValidator creates a new validator that checks if the slice has at least min elements.
+func NewSliceMinLengthValidator[T any](min int) Validator[[]T] {
+	return &sliceValidator[T]{
+		fn: func(value []T) error {
+			if len(value) < min {
+				return errors.Wrapf(ErrValidatorFailed, "expected at least %d values, got %d", min, len(value))
+			}
+			return nil
+		},
+		description: fmt.Sprintf("at least %d values", min),
+	}
+}
+
+// NewSliceMaxLengthValidator creates a new validator that checks if the slice has at most max elements.
+func NewSliceMaxLengthValidator[T any](max int) Validator[[]T] {
+	return &sliceValidator[T]{
+		fn: func(value []T) error {
+			if len(value) > max {
+				return errors.Wrapf(ErrValidatorFailed, "expected at most %d values, got %d", max, len(value))
+			}
+			return nil
+		},
+		description: fmt.Sprintf("at most %d values", max),
+	}
+}
+
+// NewSliceUniqueValidator creates a new validator that checks if all elements of the slice are unique.
+func NewSliceUniqueValidator[T comparable]() Validator[[]T] {
+	return &sliceValidator[T]{
+		fn: func(value []T) error {
+			seen := make(map[T]struct{}, len(value))
+			for _, v := range value {
+				if _, ok := seen[v]; ok {
+					return errors.Wrapf(ErrValidatorFailed, "value '%v' is repeated", v)
+				}
+				seen[v] = struct{}{}
+			}
+			return nil
+		},
+		description: "unique values",
+	}
+}
+
+// NewEachValidator creates a new validator that runs the given validator against every element of the slice.
+func NewEachValidator[T any](validator Validator[T]) Validator[[]T] {
+	return &sliceValidator[T]{
+		fn: func(value []T) error {
+			for idx, v := range value {
+				if err := validator.Validate(v); err != nil {
+					return errors.Wrapf(err, "element %d", idx)
+				}
+			}
+			return nil
+		},
+		description: describeEach(validator),
+	}
+}
+
+func describeEach(validator any) string {
+	if description := describeValidator(validator); description != "" {
+		return fmt.Sprintf("each %s", description)
+	}
+	return ""
+}
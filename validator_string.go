@@ -1,17 +1,25 @@
 package cling
 
 import (
+	"fmt"
+	"regexp"
+
 	"github.com/pkg/errors"
 )
 
 type stringValidator struct {
-	fn func(value string) error
+	fn          func(value string) error
+	description string
 }
 
 func (v *stringValidator) Validate(value string) error {
 	return v.fn(value)
 }
 
+func (v *stringValidator) Describe() string {
+	return v.description
+}
+
 var ErrStringLen = errors.New("string length is not in range")
 
 func NewStringLengthValidator(min, max int) Validator[string] {
@@ -22,5 +30,21 @@ func NewStringLengthValidator(min, max int) Validator[string] {
 			}
 			return nil
 		},
+		description: fmt.Sprintf("length between %d and %d", min, max),
+	}
+}
+
+// NewRegexValidator creates a new validator that checks if the value matches the given regular expression.
+// It panics if the expression cannot be compiled.
+func NewRegexValidator(pattern string) Validator[string] {
+	re := regexp.MustCompile(pattern)
+	return &stringValidator{
+		fn: func(value string) error {
+			if !re.MatchString(value) {
+				return errors.Wrapf(ErrValidatorFailed, "value '%s' does not match pattern '%s'", value, pattern)
+			}
+			return nil
+		},
+		description: fmt.Sprintf("matching %s", pattern),
 	}
 }
@@ -0,0 +1,102 @@
+package cling
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidators(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file.txt")
+	if err := os.WriteFile(file, []byte("test"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		run     func() error
+		wantErr bool
+	}{
+		{"range in", func() error { return NewRangeValidator(1.5, 2.5).Validate(2.0) }, false},
+		{"range out", func() error { return NewRangeValidator("b", "d").Validate("e") }, true},
+		{"regex match", func() error { return NewRegexValidator(`^v\d+$`).Validate("v12") }, false},
+		{"regex mismatch", func() error { return NewRegexValidator(`^v\d+$`).Validate("x12") }, true},
+		{"path exists", func() error { return NewPathExistsValidator().Validate(dir) }, false},
+		{"path missing", func() error { return NewPathExistsValidator().Validate(filepath.Join(dir, "nope")) }, true},
+		{"file exists", func() error { return NewFileExistsValidator().Validate(file) }, false},
+		{"file is dir", func() error { return NewFileExistsValidator().Validate(dir) }, true},
+		{"dir exists", func() error { return NewDirExistsValidator().Validate(dir) }, false},
+		{"dir is file", func() error { return NewDirExistsValidator().Validate(file) }, true},
+		{"writable file", func() error { return NewPathWritableValidator().Validate(file) }, false},
+		{"writable new file", func() error { return NewPathWritableValidator().Validate(filepath.Join(dir, "new")) }, false},
+		{"url", func() error { return NewURLValidator("https").Validate("https://example.com/x") }, false},
+		{"url scheme", func() error { return NewURLValidator("https").Validate("ftp://example.com") }, true},
+		{"url scheme case", func() error { return NewURLValidator("HTTPS").Validate("https://example.com") }, false},
+		{"url relative", func() error { return NewURLValidator().Validate("/just/a/path") }, true},
+		{"email", func() error { return NewEmailValidator().Validate("dev@example.com") }, false},
+		{"email named", func() error { return NewEmailValidator().Validate("Dev <dev@example.com>") }, true},
+		{"hostport", func() error { return NewHostPortValidator().Validate("localhost:8080") }, false},
+		{"hostport no port", func() error { return NewHostPortValidator().Validate("localhost") }, true},
+		{"hostport bad port", func() error { return NewHostPortValidator().Validate("localhost:99999") }, true},
+		{"slice min", func() error { return NewSliceMinLengthValidator[int](2).Validate([]int{1}) }, true},
+		{"slice max", func() error { return NewSliceMaxLengthValidator[int](2).Validate([]int{1, 2}) }, false},
+		{"slice unique", func() error { return NewSliceUniqueValidator[string]().Validate([]string{"a", "b", "a"}) }, true},
+		{"slice each", func() error { return NewEachValidator(NewIntRangeValidator(0, 10)).Validate([]int{1, 11}) }, true},
+		{"not", func() error { return Not(NewEnumValidator("a", "b")).Validate("a") }, true},
+		{"any of", func() error { return AnyOf(NewEnumValidator(1), NewIntRangeValidator(5, 10)).Validate(7) }, false},
+		{"any of none", func() error { return AnyOf(NewEnumValidator(1), NewIntRangeValidator(5, 10)).Validate(3) }, true},
+		{"any of empty", func() error { return AnyOf[int]().Validate(3) }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error: %v, got: %v", tt.wantErr, err)
+			}
+			if err != nil && !errors.Is(err, ErrValidatorFailed) {
+				t.Fatalf("expected error to wrap ErrValidatorFailed, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidatorDescriptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		validator any
+		want      string
+	}{
+		{"enum", NewEnumValidator("a", "b"), "one of a|b"},
+		{"int range", NewIntRangeValidator(1, 10), "between 1 and 10"},
+		{"range", NewRangeValidator(0.5, 1.5), "between 0.5 and 1.5"},
+		{"string length", NewStringLengthValidator(2, 8), "length between 2 and 8"},
+		{"regex", NewRegexValidator(`^v\d+$`), `matching ^v\d+$`},
+		{"path exists", NewPathExistsValidator(), "existing path"},
+		{"file exists", NewFileExistsValidator(), "existing file"},
+		{"dir exists", NewDirExistsValidator(), "existing directory"},
+		{"path writable", NewPathWritableValidator(), "writable path"},
+		{"url", NewURLValidator(), "URL"},
+		{"url schemes", NewURLValidator("http", "https"), "http|https URL"},
+		{"email", NewEmailValidator(), "email address"},
+		{"hostport", NewHostPortValidator(), "host:port"},
+		{"slice min", NewSliceMinLengthValidator[int](2), "at least 2 values"},
+		{"slice max", NewSliceMaxLengthValidator[int](3), "at most 3 values"},
+		{"slice unique", NewSliceUniqueValidator[string](), "unique values"},
+		{"slice each", NewEachValidator(NewIntRangeValidator(0, 10)), "each between 0 and 10"},
+		{"compose", ComposeValidator(NewStringLengthValidator(1, 3), NewEnumValidator("ab", "cd")), "length between 1 and 3, one of ab|cd"},
+		{"not", Not(NewEnumValidator("a")), "not one of a"},
+		{"any of", AnyOf(NewEnumValidator(1), NewIntRangeValidator(5, 10)), "one of 1 or between 5 and 10"},
+		{"comparator", NewComparatorValidator(func(a int) error { return nil }), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := describeValidator(tt.validator); got != tt.want {
+				t.Fatalf("expected description %q, got %q", tt.want, got)
+			}
+		})
+	}
+}